@@ -0,0 +1,193 @@
+package shopify
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type AccessScope struct {
+	Handle string `json:"handle"`
+}
+
+// AccessScopes returns the scopes granted to the API's access token.
+func (api *API) AccessScopes() ([]AccessScope, error) {
+	res, status, err := api.request("/admin/oauth/access_scopes.json", "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := map[string][]AccessScope{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r["access_scopes"], nil
+}
+
+// MissingScopes returns the required scopes not covered by granted. A
+// write_ scope covers the matching read_ scope.
+func MissingScopes(granted []string, required []string) []string {
+	have := map[string]bool{}
+	for _, scope := range granted {
+		have[scope] = true
+		if strings.HasPrefix(scope, "write_") {
+			have["read_"+strings.TrimPrefix(scope, "write_")] = true
+		}
+	}
+
+	missing := []string{}
+	for _, scope := range required {
+		if !have[scope] {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+// ScopeChecker compares the scopes each shop has granted with the scopes
+// the app requires. Granted scopes are cached per shop; call Forget after a
+// shop re-authorizes.
+type ScopeChecker struct {
+	App      *App
+	Required []string
+
+	// APIForShop returns the client holding the shop's access token.
+	APIForShop func(shop string) (*API, error)
+
+	// ShopFromRequest finds the shop for Middleware, e.g. from the app's
+	// session, and must only return shops the app has authenticated.
+	// Defaults to the shop query parameter, which is only used when the
+	// request's HMAC signature verifies against App.
+	ShopFromRequest func(r *http.Request) string
+
+	mu      sync.Mutex
+	granted map[string][]string
+}
+
+// GrantedScopes returns the scopes shop has granted, fetching them if they
+// aren't cached.
+func (c *ScopeChecker) GrantedScopes(shop string) ([]string, error) {
+	c.mu.Lock()
+	granted, ok := c.granted[shop]
+	c.mu.Unlock()
+	if ok {
+		return granted, nil
+	}
+
+	api, err := c.APIForShop(shop)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes, err := api.AccessScopes()
+	if err != nil {
+		return nil, err
+	}
+
+	granted = []string{}
+	for _, scope := range scopes {
+		granted = append(granted, scope.Handle)
+	}
+
+	c.mu.Lock()
+	if c.granted == nil {
+		c.granted = map[string][]string{}
+	}
+	c.granted[shop] = granted
+	c.mu.Unlock()
+
+	return granted, nil
+}
+
+func (c *ScopeChecker) MissingScopes(shop string) ([]string, error) {
+	granted, err := c.GrantedScopes(shop)
+	if err != nil {
+		return nil, err
+	}
+	return MissingScopes(granted, c.Required), nil
+}
+
+// NeedsReauthorization reports whether shop's token lacks required scopes.
+func (c *ScopeChecker) NeedsReauthorization(shop string) (bool, error) {
+	missing, err := c.MissingScopes(shop)
+	if err != nil {
+		return false, err
+	}
+	return len(missing) > 0, nil
+}
+
+// Forget drops the cached scopes for shop.
+func (c *ScopeChecker) Forget(shop string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.granted, shop)
+}
+
+// ReauthorizeURL is the authorize URL asking for the missing scopes along
+// with everything already granted, so no existing access is dropped.
+func (c *ScopeChecker) ReauthorizeURL(shop string) (string, error) {
+	granted, err := c.GrantedScopes(shop)
+	if err != nil {
+		return "", err
+	}
+
+	scopes := map[string]bool{}
+	for _, scope := range granted {
+		scopes[scope] = true
+	}
+	for _, scope := range MissingScopes(granted, c.Required) {
+		scopes[scope] = true
+	}
+
+	list := []string{}
+	for scope := range scopes {
+		list = append(list, scope)
+	}
+	sort.Strings(list)
+
+	return c.App.AuthorizeURL(shop, strings.Join(list, ",")), nil
+}
+
+// Middleware redirects to the authorize URL when the request's shop has
+// not granted every required scope. Requests without a verified
+// myshopify.com shop are refused.
+func (c *ScopeChecker) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		shop, status, err := requestShop(c.App, r, c.ShopFromRequest)
+		if err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		needed, err := c.NeedsReauthorization(shop)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		if needed {
+			redirect, err := c.ReauthorizeURL(shop)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			// fetch again once the shop comes back from authorizing
+			c.Forget(shop)
+			http.Redirect(w, r, redirect, http.StatusFound)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,98 @@
+package shopify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// newTestAPI returns an API that sends its requests to ts.
+func newTestAPI(ts *httptest.Server) *API {
+	return &API{
+		Shop:        strings.TrimPrefix(ts.URL, "https://"),
+		AccessToken: "token",
+		client:      ts.Client(),
+	}
+}
+
+// signQuery adds an hmac for secret to query, like Shopify does for app
+// requests.
+func signQuery(secret string, query string) string {
+	u := &url.URL{RawQuery: query}
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte((&App{}).signatureString(u, false)))
+	return query + "&hmac=" + hex.EncodeToString(h.Sum(nil))
+}
+
+func TestMissingScopes(t *testing.T) {
+	missing := MissingScopes(
+		[]string{"write_products", "read_orders"},
+		[]string{"read_products", "read_orders", "write_orders", "read_customers"},
+	)
+
+	if strings.Join(missing, ",") != "write_orders,read_customers" {
+		t.Errorf("unexpected missing scopes: %v", missing)
+	}
+}
+
+func TestScopeCheckerMiddleware(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/oauth/access_scopes.json" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"access_scopes":[{"handle":"read_products"},{"handle":"write_orders"}]}`))
+	}))
+	defer ts.Close()
+
+	checker := &ScopeChecker{
+		App:      &app,
+		Required: []string{"read_orders", "read_customers"},
+		APIForShop: func(shop string) (*API, error) {
+			return newTestAPI(ts), nil
+		},
+	}
+
+	handler := checker.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	req := httptest.NewRequest("GET", "/admin?"+signQuery(app.APISecret, "shop=burnsmod.myshopify.com&timestamp=1337178173"), nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusFound {
+		t.Fatalf("expected a redirect, got %d", w.Code)
+	}
+	expected := app.AuthorizeURL("burnsmod.myshopify.com", "read_customers,read_products,write_orders")
+	if location := w.Header().Get("Location"); location != expected {
+		t.Errorf("expected redirect to %s, got %s", expected, location)
+	}
+
+	checker.Required = []string{"read_orders"}
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusTeapot {
+		t.Errorf("expected the request to pass through, got %d", w.Code)
+	}
+
+	rejected := map[string]int{
+		"/admin?shop=burnsmod.myshopify.com":                                 http.StatusUnauthorized,
+		"/admin?" + signQuery("wrong", "shop=burnsmod.myshopify.com"):        http.StatusUnauthorized,
+		"/admin?" + signQuery(app.APISecret, "shop=evil.com"):                http.StatusBadRequest,
+		"/admin?" + signQuery(app.APISecret, "shop=evil.com/.myshopify.com"): http.StatusBadRequest,
+		"/admin": http.StatusBadRequest,
+	}
+	for target, expected := range rejected {
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+		if w.Code != expected {
+			t.Errorf("%s: expected %d, got %d", target, expected, w.Code)
+		}
+	}
+}
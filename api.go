@@ -47,6 +47,10 @@ type RequestCache interface {
 	Set(string, *bytes.Buffer)
 }
 
+// noCache is passed as params to keep a response out of the RequestCache,
+// for requests that are polled or change state.
+var noCache = map[string]interface{}{}
+
 type Pages struct {
 	prevPage string
 	nextPage string
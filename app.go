@@ -11,8 +11,10 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"regexp"
 	"sort"
 	"strings"
+	"time"
 )
 
 type App struct {
@@ -20,6 +22,64 @@ type App struct {
 	APISecret       string
 	RedirectURI     string
 	IgnoreSignature bool
+
+	// Secrets are additional secrets accepted when verifying requests, tried
+	// in order after APISecret. Use them to keep the old secret valid while
+	// a rotation rolls out. If APISecret is empty the first entry is primary.
+	Secrets []AppSecret
+}
+
+// AppSecret is a verification secret with an optional validity window.
+// Zero NotBefore or NotAfter leave that side of the window open.
+type AppSecret struct {
+	Label     string
+	Secret    string
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+func (secret AppSecret) validAt(t time.Time) bool {
+	if !secret.NotBefore.IsZero() && t.Before(secret.NotBefore) {
+		return false
+	}
+	if !secret.NotAfter.IsZero() && t.After(secret.NotAfter) {
+		return false
+	}
+	return true
+}
+
+// PrimarySecret is the secret used for signing and token exchange.
+func (s *App) PrimarySecret() string {
+	if s.APISecret == "" && len(s.Secrets) > 0 {
+		return s.Secrets[0].Secret
+	}
+	return s.APISecret
+}
+
+// verificationSecrets returns the secrets valid now, primary first.
+func (s *App) verificationSecrets() []AppSecret {
+	secrets := []AppSecret{}
+	if s.APISecret != "" {
+		secrets = append(secrets, AppSecret{Label: "primary", Secret: s.APISecret})
+	}
+	now := time.Now()
+	for _, secret := range s.Secrets {
+		if secret.Secret != "" && secret.validAt(now) {
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets
+}
+
+// matchSecret returns the first valid secret for which verify succeeds.
+func (s *App) matchSecret(verify func(secret string) bool) (*AppSecret, bool) {
+	for _, secret := range s.verificationSecrets() {
+		if verify(secret.Secret) {
+			matched := secret
+			return &matched, true
+		}
+	}
+	return nil, false
 }
 
 func (s *App) AuthorizeURL(shop string, scopes string) string {
@@ -36,6 +96,47 @@ func (s *App) AuthorizeURL(shop string, scopes string) string {
 	return u.String()
 }
 
+var shopDomain = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9-]*\.myshopify\.com$`)
+
+// ValidShopDomain reports whether shop is a shop's myshopify.com domain,
+// e.g. burnsmod.myshopify.com.
+func ValidShopDomain(shop string) bool {
+	return shopDomain.MatchString(shop)
+}
+
+// requestShop returns the shop a request is for, with the HTTP status to
+// respond with on failure. shopFromRequest is trusted if set; otherwise the
+// shop query parameter is used and the request's HMAC signature must
+// verify. Either way the shop must be a myshopify.com domain, so it is safe
+// to call and redirect to.
+func requestShop(app *App, r *http.Request, shopFromRequest func(r *http.Request) string) (string, int, error) {
+	var shop string
+	if shopFromRequest != nil {
+		shop = shopFromRequest(r)
+	} else {
+		shop = r.URL.Query().Get("shop")
+	}
+	if shop == "" {
+		return "", http.StatusBadRequest, fmt.Errorf("Expected 'shop' param")
+	}
+
+	if shopFromRequest == nil && (app == nil || !app.VerifyHMACSignature(r.URL)) {
+		return "", http.StatusUnauthorized, fmt.Errorf("Invalid signature")
+	}
+	if !ValidShopDomain(shop) {
+		return "", http.StatusBadRequest, fmt.Errorf("Invalid shop domain: %s", shop)
+	}
+	return shop, 0, nil
+}
+
+// signParams returns the hmac for params, signed the way Shopify signs app
+// requests, with the primary secret.
+func (s *App) signParams(params url.Values) string {
+	h := hmac.New(sha256.New, []byte(s.PrimarySecret()))
+	h.Write([]byte(s.signatureString(&url.URL{RawQuery: params.Encode()}, false)))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 func VerifyHMAC(expectedHMAC, message, sharedSecret string) bool {
 	h := hmac.New(sha256.New, []byte(sharedSecret))
 	h.Write([]byte(message))
@@ -45,31 +146,47 @@ func VerifyHMAC(expectedHMAC, message, sharedSecret string) bool {
 }
 
 func (s *App) VerifyHMACSignature(u *url.URL) bool {
+	_, ok := s.MatchHMACSignature(u)
+	return ok
+}
+
+// MatchHMACSignature is VerifyHMACSignature, also returning the secret that
+// matched. The secret is nil when IgnoreSignature is set.
+func (s *App) MatchHMACSignature(u *url.URL) (*AppSecret, bool) {
 	if s.IgnoreSignature {
-		return true
+		return nil, true
 	}
 	params := u.Query()
 	hmac := params.Get("hmac")
 	if params.Get("shop") == "" {
-		return false
+		return nil, false
 	}
-	params.Del("hmac")
-	params.Del("signature")
 	message := s.signatureString(u, false)
-	return VerifyHMAC(hmac, message, s.APISecret)
+	return s.matchSecret(func(secret string) bool {
+		return VerifyHMAC(hmac, message, secret)
+	})
 }
 
 func (s *App) VerifyHookRequest(r *http.Request, body []byte) bool {
+	_, ok := s.MatchHookRequest(r, body)
+	return ok
+}
+
+// MatchHookRequest is VerifyHookRequest, also returning the secret that
+// matched. The secret is nil when IgnoreSignature is set.
+func (s *App) MatchHookRequest(r *http.Request, body []byte) (*AppSecret, bool) {
 	if s.IgnoreSignature {
-		return true
+		return nil, true
 	}
 	expectedHMAC := r.Header.Get("X-Shopify-Hmac-SHA256")
 
-	h := hmac.New(sha256.New, []byte(s.APISecret))
-	h.Write([]byte(string(body)))
+	return s.matchSecret(func(secret string) bool {
+		h := hmac.New(sha256.New, []byte(secret))
+		h.Write(body)
 
-	value := base64.StdEncoding.EncodeToString(h.Sum(nil))
-	return hmac.Equal([]byte(expectedHMAC), []byte(value))
+		value := base64.StdEncoding.EncodeToString(h.Sum(nil))
+		return hmac.Equal([]byte(expectedHMAC), []byte(value))
+	})
 }
 
 // Deprecated and removed on 1st Jun 2015
@@ -91,21 +208,31 @@ func (s *App) VerifyHookRequest(r *http.Request, body []byte) bool {
 //}
 
 func (s *App) AppProxySignatureOk(u *url.URL) bool {
+	_, ok := s.MatchAppProxySignature(u)
+	return ok
+}
+
+// MatchAppProxySignature is AppProxySignatureOk, also returning the secret
+// that matched. The secret is nil when IgnoreSignature is set.
+func (s *App) MatchAppProxySignature(u *url.URL) (*AppSecret, bool) {
 	if s.IgnoreSignature {
-		return true
+		return nil, true
 	}
 
 	params := u.Query()
 	signature := params.Get("signature")
 	if signature == "" {
-		return false
+		return nil, false
 	}
 
-	mac := hmac.New(sha256.New, []byte(s.APISecret))
-	mac.Write([]byte(s.signatureString(u, false)))
-	calculated := hex.EncodeToString(mac.Sum(nil))
+	message := appProxySignatureString(u)
+	return s.matchSecret(func(secret string) bool {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(message))
+		calculated := hex.EncodeToString(mac.Sum(nil))
 
-	return 1 == subtle.ConstantTimeCompare([]byte(signature), []byte(calculated))
+		return 1 == subtle.ConstantTimeCompare([]byte(signature), []byte(calculated))
+	})
 }
 
 func (s *App) signatureString(u *url.URL, prependSig bool) string {
@@ -125,7 +252,7 @@ func (s *App) signatureString(u *url.URL, prependSig bool) string {
 	}
 
 	if prependSig {
-		return fmt.Sprintf("%v%v", s.APISecret, strings.Join(inputs, "&"))
+		return fmt.Sprintf("%v%v", s.PrimarySecret(), strings.Join(inputs, "&"))
 	}
 	return strings.Join(inputs, "&")
 }
@@ -135,7 +262,7 @@ func (s *App) AccessToken(shop string, code string) (string, error) {
 
 	data := map[string]string{
 		"client_id":     s.APIKey,
-		"client_secret": s.APISecret,
+		"client_secret": s.PrimarySecret(),
 		"code":          code,
 	}
 
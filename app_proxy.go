@@ -0,0 +1,115 @@
+package shopify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Responses with this content type are rendered by Shopify inside the theme.
+const LiquidContentType = "application/liquid"
+
+// AppProxyRequest holds the parameters Shopify adds to app proxy requests.
+type AppProxyRequest struct {
+	Shop       string
+	PathPrefix string
+	Timestamp  time.Time
+
+	// LoggedInCustomerID is 0 when no customer is logged in.
+	LoggedInCustomerID int64
+}
+
+type appProxyContextKey struct{}
+
+// AppProxyFromContext returns the proxy parameters stored by
+// AppProxyMiddleware.
+func AppProxyFromContext(ctx context.Context) (*AppProxyRequest, bool) {
+	proxy, ok := ctx.Value(appProxyContextKey{}).(*AppProxyRequest)
+	return proxy, ok
+}
+
+// AppProxyMiddleware rejects app proxy requests without a valid signature
+// and puts the proxy parameters on the request context for next.
+func (s *App) AppProxyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.AppProxySignatureOk(r.URL) {
+			http.Error(w, "Invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		proxy, err := parseAppProxyRequest(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), appProxyContextKey{}, proxy)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func parseAppProxyRequest(params url.Values) (*AppProxyRequest, error) {
+	proxy := &AppProxyRequest{
+		Shop:       params.Get("shop"),
+		PathPrefix: params.Get("path_prefix"),
+	}
+
+	if ts := params.Get("timestamp"); ts != "" {
+		seconds, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid timestamp: %s", ts)
+		}
+		proxy.Timestamp = time.Unix(seconds, 0)
+	}
+
+	if id := params.Get("logged_in_customer_id"); id != "" {
+		customerID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid logged_in_customer_id: %s", id)
+		}
+		proxy.LoggedInCustomerID = customerID
+	}
+
+	return proxy, nil
+}
+
+// appProxySignatureString builds the message Shopify signs for app proxy
+// requests: sorted key=value pairs with no separator, multiple values
+// joined by commas.
+func appProxySignatureString(u *url.URL) string {
+	params := u.Query()
+
+	keys := []string{}
+	for k := range params {
+		if k != "signature" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	message := ""
+	for _, k := range keys {
+		message += fmt.Sprintf("%s=%s", k, strings.Join(params[k], ","))
+	}
+	return message
+}
+
+// WriteLiquid responds with Liquid that Shopify renders inside the theme
+// layout.
+func WriteLiquid(w http.ResponseWriter, liquid string) error {
+	w.Header().Set("Content-Type", LiquidContentType)
+	_, err := io.WriteString(w, liquid)
+	return err
+}
+
+// WriteLiquidWithoutLayout responds with Liquid rendered on its own,
+// without the theme layout around it.
+func WriteLiquidWithoutLayout(w http.ResponseWriter, liquid string) error {
+	return WriteLiquid(w, "{% layout none %}"+liquid)
+}
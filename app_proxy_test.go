@@ -0,0 +1,50 @@
+package shopify
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// the example request from Shopify's app proxy docs, plus a logged in
+// customer, signed with "hush"
+const proxyQuery = "extra=1&extra=2&shop=shop-name.myshopify.com&logged_in_customer_id=1&path_prefix=%2Fapps%2Fawesome_reviews&timestamp=1317327555&signature=4c68c8624d737112c91818c11017d24d334b524cb5c2b8ba08daa056f7395ddb"
+
+func TestAppProxyMiddleware(t *testing.T) {
+	a := App{APISecret: "hush"}
+
+	var proxy *AppProxyRequest
+	handler := a.AppProxyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxy, _ = AppProxyFromContext(r.Context())
+		WriteLiquidWithoutLayout(w, "{{ shop.name }}")
+	}))
+
+	req := httptest.NewRequest("GET", "/app_proxy/reviews?"+proxyQuery, nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if proxy == nil {
+		t.Fatalf("proxy parameters missing from context")
+	}
+	if proxy.Shop != "shop-name.myshopify.com" || proxy.PathPrefix != "/apps/awesome_reviews" ||
+		proxy.Timestamp.Unix() != 1317327555 || proxy.LoggedInCustomerID != 1 {
+		t.Errorf("unexpected proxy parameters: %#v", proxy)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != LiquidContentType {
+		t.Errorf("expected %s, got %s", LiquidContentType, ct)
+	}
+	if w.Body.String() != "{% layout none %}{{ shop.name }}" {
+		t.Errorf("unexpected body: %s", w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/app_proxy/reviews?shop=shop-name.myshopify.com&signature=ffff", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for a bad signature, got %d", w.Code)
+	}
+}
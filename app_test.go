@@ -1,8 +1,11 @@
 package shopify
 
 import (
+	"bytes"
+	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 )
 
 var app App
@@ -57,3 +60,52 @@ func TestIgnoreSignature(t *testing.T) {
 		t.Errorf("IgnoreSignature didn't work for AppProxy")
 	}
 }
+
+func TestRotatedSecrets(t *testing.T) {
+	u, _ := url.Parse("https://app.com/?hmac=89f3e7c84239719b8f5dc2c7bf743e884d7dc49c9de2847d3e3c38d1b4ad7b93&shop=burnsmod.myshopify.com&code=asdf&timestamp=1337178173&signature=bd28a1a098688d8937e991aef3bc80ab")
+
+	a := App{APIKey: "asdf", APISecret: "5678", Secrets: []AppSecret{{Label: "old", Secret: "1234"}}}
+
+	secret, ok := a.MatchHMACSignature(u)
+	if !ok {
+		t.Fatalf("signature signed with the old secret was rejected")
+	}
+	if secret.Label != "old" {
+		t.Errorf("expected the old secret to match, got %s", secret.Label)
+	}
+
+	if a.PrimarySecret() != "5678" {
+		t.Errorf("expected APISecret to stay primary, got %s", a.PrimarySecret())
+	}
+
+	if output := a.signatureString(u, true); output != "5678code=asdf&shop=burnsmod.myshopify.com&timestamp=1337178173" {
+		t.Errorf("expected signing to use the primary secret, got %s", output)
+	}
+
+	a.Secrets[0].NotAfter = time.Now().Add(-time.Minute)
+	if a.VerifyHMACSignature(u) {
+		t.Errorf("expired secret was accepted")
+	}
+
+	a.Secrets[0].NotAfter = time.Time{}
+	a.Secrets[0].NotBefore = time.Now().Add(time.Minute)
+	if a.VerifyHMACSignature(u) {
+		t.Errorf("secret was accepted before its window opened")
+	}
+}
+
+func TestRotatedSecretsHookRequest(t *testing.T) {
+	body := []byte(`{"id":1}`)
+	req := httptest.NewRequest("POST", "/hooks", bytes.NewReader(body))
+	req.Header.Set("X-Shopify-Hmac-SHA256", signHook("1234", body))
+
+	a := App{APISecret: "5678", Secrets: []AppSecret{{Label: "old", Secret: "1234"}}}
+	if secret, ok := a.MatchHookRequest(req, body); !ok || secret.Label != "old" {
+		t.Errorf("expected hook to match the old secret, got %v %v", secret, ok)
+	}
+
+	a.Secrets = nil
+	if a.VerifyHookRequest(req, body) {
+		t.Errorf("hook signed with a retired secret was accepted")
+	}
+}
@@ -0,0 +1,173 @@
+package shopify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+type ApplicationCharge struct {
+	ConfirmationURL    string `json:"confirmation_url,omitempty"`
+	CreatedAt          string `json:"created_at,omitempty"`
+	DecoratedReturnURL string `json:"decorated_return_url,omitempty"`
+	ID                 int64  `json:"id,omitempty"`
+	Name               string `json:"name,omitempty"`
+	Price              string `json:"price,omitempty"`
+	ReturnURL          string `json:"return_url,omitempty"`
+	Status             string `json:"status,omitempty"`
+	Test               bool   `json:"test,omitempty"`
+	UpdatedAt          string `json:"updated_at,omitempty"`
+
+	api *API
+}
+
+type ApplicationChargeOptions struct {
+	SinceID int64  `url:"since_id,omitempty"`
+	Fields  string `url:"fields,omitempty"`
+}
+
+// ApplicationCharges Retrieve all one-time application charges
+func (api *API) ApplicationCharges(options *ApplicationChargeOptions) ([]*ApplicationCharge, error) {
+
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("BASE_PATH/application_charges.json?%v", qs)
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := &map[string][]*ApplicationCharge{}
+	err = json.NewDecoder(res).Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := (*r)["application_charges"]
+	for _, v := range result {
+		v.api = api
+	}
+
+	return result, nil
+}
+
+func (api *API) ApplicationCharge(id int64) (*ApplicationCharge, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/application_charges/%d.json", id)
+
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := map[string]ApplicationCharge{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	result := r["application_charge"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.api = api
+
+	return &result, nil
+}
+
+func (api *API) NewApplicationCharge() *ApplicationCharge {
+	return &ApplicationCharge{api: api}
+}
+
+func (obj *ApplicationCharge) Save() error {
+
+	endpoint := fmt.Sprintf("BASE_PATH/application_charges.json")
+	method := "POST"
+	expectedStatus := 201
+
+	body := map[string]*ApplicationCharge{}
+	body["application_charge"] = obj
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	res, status, err := obj.api.request(endpoint, method, nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return fmt.Errorf("Status %d: %v", status, r.Errors)
+		} else {
+			return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+		}
+	}
+
+	r := map[string]ApplicationCharge{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	api := obj.api
+	*obj = r["application_charge"]
+	obj.api = api
+
+	return nil
+}
+
+// Activate an accepted charge. Newer API versions activate charges as soon
+// as the merchant accepts them.
+func (obj *ApplicationCharge) Activate() error {
+	endpoint := fmt.Sprintf("BASE_PATH/application_charges/%d/activate.json", obj.ID)
+	method := "POST"
+	expectedStatus := 200
+
+	res, status, err := obj.api.request(endpoint, method, noCache, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return fmt.Errorf("Status %d: %v", status, r.Errors)
+		} else {
+			return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+		}
+	}
+
+	r := map[string]ApplicationCharge{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	if activated, ok := r["application_charge"]; ok {
+		api := obj.api
+		*obj = activated
+		obj.api = api
+	}
+
+	return nil
+}
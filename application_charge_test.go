@@ -0,0 +1,86 @@
+package shopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestApplicationCharges(t *testing.T) {
+	var created map[string]ApplicationCharge
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /admin/api/2021-07/application_charges.json":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(201)
+			w.Write([]byte(`{"application_charge":{"id":675931192,"name":"Super Duper Expensive action","price":"100.00","status":"pending","test":true,"confirmation_url":"https://jsmith.myshopify.com/admin/charges/675931192/confirm_application_charge?signature=abc"}}`))
+		case "GET /admin/api/2021-07/application_charges/675931192.json":
+			w.Write([]byte(`{"application_charge":{"id":675931192,"name":"Super Duper Expensive action","price":"100.00","status":"accepted"}}`))
+		case "GET /admin/api/2021-07/application_charges.json":
+			if r.URL.Query().Get("since_id") != "556467234" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"application_charges":[{"id":675931192,"status":"accepted"},{"id":1017262355,"status":"pending"}]}`))
+		case "POST /admin/api/2021-07/application_charges/675931192/activate.json":
+			w.Write([]byte(`{"application_charge":{"id":675931192,"name":"Super Duper Expensive action","price":"100.00","status":"active"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	api := newTestAPI(ts)
+
+	charge := api.NewApplicationCharge()
+	charge.Name = "Super Duper Expensive action"
+	charge.Price = "100.00"
+	charge.ReturnURL = "https://app.com/charges"
+	charge.Test = true
+	if err := charge.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if sent := created["application_charge"]; sent.Name != charge.Name || sent.Price != "100.00" || !sent.Test {
+		t.Errorf("unexpected create body %+v", created)
+	}
+	if charge.ID != 675931192 || charge.ConfirmationURL == "" || charge.api == nil {
+		t.Errorf("unexpected charge %+v", charge)
+	}
+
+	charge, err := api.ApplicationCharge(675931192)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if charge.Status != "accepted" {
+		t.Errorf("unexpected charge %+v", charge)
+	}
+
+	charges, err := api.ApplicationCharges(&ApplicationChargeOptions{SinceID: 556467234})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(charges) != 2 || charges[1].ID != 1017262355 || charges[1].api == nil {
+		t.Errorf("unexpected charges %+v", charges)
+	}
+
+	if err := charge.Activate(); err != nil {
+		t.Fatal(err)
+	}
+	if charge.Status != "active" || charge.api == nil {
+		t.Errorf("charge not activated: %+v", charge)
+	}
+}
+
+func TestApplicationChargeErrors(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(422)
+		w.Write([]byte(`{"errors":{"price":["must be greater than or equal to 0.5"]}}`))
+	}))
+	defer ts.Close()
+
+	charge := newTestAPI(ts).NewApplicationCharge()
+	charge.Price = "0.10"
+	if err := charge.Save(); err == nil || err.Error() != "Status 422: map[price:[must be greater than or equal to 0.5]]" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package shopify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+type ApplicationCredit struct {
+	Amount      string `json:"amount,omitempty"`
+	Description string `json:"description,omitempty"`
+	ID          int64  `json:"id,omitempty"`
+	Test        bool   `json:"test,omitempty"`
+
+	api *API
+}
+
+type ApplicationCreditOptions struct {
+	Fields string `url:"fields,omitempty"`
+}
+
+// ApplicationCredits Retrieve all application credits
+func (api *API) ApplicationCredits(options *ApplicationCreditOptions) ([]*ApplicationCredit, error) {
+
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("BASE_PATH/application_credits.json?%v", qs)
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := &map[string][]*ApplicationCredit{}
+	err = json.NewDecoder(res).Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := (*r)["application_credits"]
+	for _, v := range result {
+		v.api = api
+	}
+
+	return result, nil
+}
+
+func (api *API) ApplicationCredit(id int64) (*ApplicationCredit, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/application_credits/%d.json", id)
+
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := map[string]ApplicationCredit{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	result := r["application_credit"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.api = api
+
+	return &result, nil
+}
+
+func (api *API) NewApplicationCredit() *ApplicationCredit {
+	return &ApplicationCredit{api: api}
+}
+
+func (obj *ApplicationCredit) Save() error {
+
+	endpoint := fmt.Sprintf("BASE_PATH/application_credits.json")
+	method := "POST"
+	expectedStatus := 201
+
+	body := map[string]*ApplicationCredit{}
+	body["application_credit"] = obj
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	res, status, err := obj.api.request(endpoint, method, nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return fmt.Errorf("Status %d: %v", status, r.Errors)
+		} else {
+			return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+		}
+	}
+
+	r := map[string]ApplicationCredit{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	api := obj.api
+	*obj = r["application_credit"]
+	obj.api = api
+
+	return nil
+}
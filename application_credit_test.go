@@ -0,0 +1,52 @@
+package shopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestApplicationCredits(t *testing.T) {
+	var created map[string]ApplicationCredit
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /admin/api/2021-07/application_credits.json":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(201)
+			w.Write([]byte(`{"application_credit":{"id":1031636125,"amount":"5.00","description":"application credit for refund","test":true}}`))
+		case "GET /admin/api/2021-07/application_credits.json":
+			if r.URL.Query().Get("fields") != "id,amount" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"application_credits":[{"id":140583599,"amount":"5.00"},{"id":1031636125,"amount":"5.00"}]}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	api := newTestAPI(ts)
+
+	credit := api.NewApplicationCredit()
+	credit.Description = "application credit for refund"
+	credit.Amount = "5.00"
+	credit.Test = true
+	if err := credit.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if sent := created["application_credit"]; sent.Amount != "5.00" || sent.Description != credit.Description || !sent.Test {
+		t.Errorf("unexpected create body %+v", created)
+	}
+	if credit.ID != 1031636125 || credit.api == nil {
+		t.Errorf("unexpected credit %+v", credit)
+	}
+
+	credits, err := api.ApplicationCredits(&ApplicationCreditOptions{Fields: "id,amount"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(credits) != 2 || credits[0].ID != 140583599 || credits[0].api == nil {
+		t.Errorf("unexpected credits %+v", credits)
+	}
+}
@@ -0,0 +1,289 @@
+package shopify
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// BillingPlan is one entry in the app's plan catalog.
+type BillingPlan struct {
+	Name      string
+	Price     string
+	TrialDays int
+	Test      bool
+}
+
+// BillingManager runs the recurring charge flow for a catalog of plans:
+// create the charge, send the merchant to confirm it, then activate it
+// when they come back.
+//
+// A request with shop and plan parameters starts a subscription; Shopify
+// sends the merchant back to ReturnURL with charge_id added, which the
+// manager also handles. The shop comes from ShopFromRequest, or else from a
+// shop parameter signed by App. Shopify doesn't sign the return from the
+// confirmation page, so the manager signs ReturnURL itself.
+type BillingManager struct {
+	// App verifies the shop parameter and signs ReturnURL when
+	// ShopFromRequest isn't set.
+	App *App
+
+	// ShopFromRequest finds the shop for a request, e.g. from the app's
+	// session, and must only return shops the app has authenticated.
+	ShopFromRequest func(r *http.Request) string
+
+	Plans []BillingPlan
+
+	// ReturnURL is the absolute URL the manager is served on.
+	ReturnURL string
+
+	// SuccessURL and DeclinedURL are where merchants are sent after
+	// confirming or declining. The shop parameter is added to both.
+	SuccessURL  string
+	DeclinedURL string
+
+	// Test marks every charge as a test charge, whatever the plan says.
+	Test bool
+
+	APIForShop func(shop string) (*API, error)
+
+	// OnActivated is called once a charge is active.
+	OnActivated func(shop string, charge *RecurringApplicationCharge)
+}
+
+func (m *BillingManager) Plan(name string) (*BillingPlan, bool) {
+	for i := range m.Plans {
+		if m.Plans[i].Name == name {
+			return &m.Plans[i], true
+		}
+	}
+	return nil, false
+}
+
+// ActiveCharge returns the shop's active recurring charge, or nil if it
+// has none.
+func (m *BillingManager) ActiveCharge(shop string) (*RecurringApplicationCharge, error) {
+	api, err := m.APIForShop(shop)
+	if err != nil {
+		return nil, err
+	}
+	return activeCharge(api)
+}
+
+func activeCharge(api *API) (*RecurringApplicationCharge, error) {
+	charges, err := api.recurringApplicationCharges(nil, noCache)
+	if err != nil {
+		return nil, err
+	}
+	for _, charge := range charges {
+		if charge.Status == StatusActive {
+			return charge, nil
+		}
+	}
+	return nil, nil
+}
+
+// Subscribe creates a pending charge for plan; the merchant must visit its
+// ConfirmationURL to accept it. If the shop is already on the plan, its
+// active charge is returned instead. When the shop is changing plans, trial
+// days left on the current charge carry over instead of starting a new
+// trial.
+func (m *BillingManager) Subscribe(shop string, planName string) (*RecurringApplicationCharge, error) {
+	plan, ok := m.Plan(planName)
+	if !ok {
+		return nil, fmt.Errorf("Unknown plan: %s", planName)
+	}
+
+	api, err := m.APIForShop(shop)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := activeCharge(api)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil && m.onPlan(current, plan) {
+		return current, nil
+	}
+
+	trialDays := plan.TrialDays
+	if current != nil {
+		trialDays = remainingTrialDays(current, time.Now())
+		if trialDays > plan.TrialDays {
+			trialDays = plan.TrialDays
+		}
+	}
+
+	returnURL, err := url.Parse(m.ReturnURL)
+	if err != nil {
+		return nil, err
+	}
+	q := returnURL.Query()
+	q.Set("shop", shop)
+	if m.App != nil {
+		q.Set("timestamp", strconv.FormatInt(time.Now().Unix(), 10))
+		q.Set("hmac", m.App.signParams(q))
+	}
+	returnURL.RawQuery = q.Encode()
+
+	charge := api.NewRecurringApplicationCharge()
+	charge.Name = plan.Name
+	charge.Price = plan.Price
+	charge.TrialDays = trialDays
+	charge.Test = plan.Test || m.Test
+	charge.ReturnURL = returnURL.String()
+
+	if err := charge.Save(); err != nil {
+		return nil, err
+	}
+
+	return charge, nil
+}
+
+// onPlan reports whether charge already bills for plan.
+func (m *BillingManager) onPlan(charge *RecurringApplicationCharge, plan *BillingPlan) bool {
+	if charge.Name != plan.Name || charge.Test != (plan.Test || m.Test) {
+		return false
+	}
+	price, err := parseCents(charge.Price)
+	if err != nil {
+		return false
+	}
+	planPrice, err := parseCents(plan.Price)
+	return err == nil && price == planPrice
+}
+
+// Complete handles the merchant's return from the confirmation page. It
+// activates an accepted charge and cancels any charge it replaces. The
+// returned charge is nil if the merchant declined.
+func (m *BillingManager) Complete(shop string, chargeID int64) (*RecurringApplicationCharge, error) {
+	api, err := m.APIForShop(shop)
+	if err != nil {
+		return nil, err
+	}
+
+	charge, err := api.recurringApplicationCharge(chargeID, noCache)
+	if err != nil {
+		return nil, err
+	}
+
+	switch charge.Status {
+	case StatusAccepted:
+		if err := charge.Activate(); err != nil {
+			return nil, err
+		}
+	case StatusActive:
+		// newer API versions activate charges when they are accepted
+	case StatusDeclined:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("Charge %d is %s", charge.ID, charge.Status)
+	}
+
+	charges, err := api.recurringApplicationCharges(nil, noCache)
+	if err != nil {
+		return nil, err
+	}
+	for _, old := range charges {
+		if old.ID != charge.ID && old.Status == StatusActive {
+			if err := old.Delete(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if m.OnActivated != nil {
+		m.OnActivated(shop, charge)
+	}
+
+	return charge, nil
+}
+
+func (m *BillingManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	// the return URL was signed before Shopify added charge_id
+	signed := r
+	if params.Get("charge_id") != "" {
+		u := *r.URL
+		q := u.Query()
+		q.Del("charge_id")
+		u.RawQuery = q.Encode()
+		signed = r.WithContext(r.Context())
+		signed.URL = &u
+	}
+
+	shop, status, err := requestShop(m.App, signed, m.ShopFromRequest)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	if id := params.Get("charge_id"); id != "" {
+		chargeID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid charge_id", http.StatusBadRequest)
+			return
+		}
+
+		charge, err := m.Complete(shop, chargeID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		next := m.SuccessURL
+		if charge == nil {
+			next = m.DeclinedURL
+		}
+		http.Redirect(w, r, withShop(next, shop), http.StatusFound)
+		return
+	}
+
+	charge, err := m.Subscribe(shop, params.Get("plan"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if charge.Status == StatusActive {
+		http.Redirect(w, r, withShop(m.SuccessURL, shop), http.StatusFound)
+		return
+	}
+	http.Redirect(w, r, charge.ConfirmationURL, http.StatusFound)
+}
+
+func withShop(rawURL string, shop string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || rawURL == "" {
+		u = &url.URL{Path: "/"}
+	}
+	q := u.Query()
+	q.Set("shop", shop)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// remainingTrialDays returns the whole trial days left on charge at now.
+func remainingTrialDays(charge *RecurringApplicationCharge, now time.Time) int {
+	if charge.TrialEndsOn == "" {
+		return 0
+	}
+
+	var ends time.Time
+	var err error
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		ends, err = time.Parse(layout, charge.TrialEndsOn)
+		if err == nil {
+			break
+		}
+	}
+	if err != nil || !ends.After(now) {
+		return 0
+	}
+
+	return int(math.Ceil(ends.Sub(now).Hours() / 24))
+}
@@ -0,0 +1,161 @@
+package shopify
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BillingStandIn is a local stand-in for Shopify's recurring charge
+// endpoints for a single shop, so the billing flow can run without a
+// development store.
+//
+// Its confirmation page accepts the charge, or declines it when called
+// with decline=1, and redirects to the charge's return URL like Shopify.
+type BillingStandIn struct {
+	Server *httptest.Server
+
+	mu      sync.Mutex
+	nextID  int64
+	charges map[int64]*RecurringApplicationCharge
+}
+
+// NewBillingStandIn starts a stand-in server. Call Close when done.
+func NewBillingStandIn() *BillingStandIn {
+	s := &BillingStandIn{nextID: 1, charges: map[int64]*RecurringApplicationCharge{}}
+	s.Server = httptest.NewTLSServer(s)
+	return s
+}
+
+func (s *BillingStandIn) Close() {
+	s.Server.Close()
+}
+
+// API returns a client whose requests go to the stand-in.
+func (s *BillingStandIn) API() *API {
+	return &API{
+		Shop:        strings.TrimPrefix(s.Server.URL, "https://"),
+		AccessToken: "stand-in",
+		client:      s.Server.Client(),
+	}
+}
+
+// Charge returns a copy of the stand-in's state for charge id.
+func (s *BillingStandIn) Charge(id int64) (RecurringApplicationCharge, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	charge, ok := s.charges[id]
+	if !ok {
+		return RecurringApplicationCharge{}, false
+	}
+	return *charge, true
+}
+
+func (s *BillingStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := r.URL.Path
+	if strings.HasPrefix(path, "/confirm/") {
+		s.confirm(w, r, strings.TrimPrefix(path, "/confirm/"))
+		return
+	}
+
+	i := strings.Index(path, "/recurring_application_charges")
+	if i < 0 {
+		http.NotFound(w, r)
+		return
+	}
+	rest := strings.TrimSuffix(strings.TrimPrefix(path[i:], "/recurring_application_charges"), ".json")
+
+	switch {
+	case rest == "" && r.Method == "GET":
+		list := []*RecurringApplicationCharge{}
+		for id := int64(1); id < s.nextID; id++ {
+			if charge, ok := s.charges[id]; ok {
+				list = append(list, charge)
+			}
+		}
+		writeStandInJSON(w, 200, "recurring_application_charges", list)
+	case rest == "" && r.Method == "POST":
+		body := map[string]*RecurringApplicationCharge{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["recurring_application_charge"] == nil {
+			writeStandInJSON(w, 422, "errors", map[string]string{"base": "invalid charge"})
+			return
+		}
+		charge := body["recurring_application_charge"]
+		charge.ID = s.nextID
+		s.nextID++
+		charge.Status = StatusPending
+		charge.CreatedAt = time.Now().Format(time.RFC3339)
+		charge.ConfirmationURL = fmt.Sprintf("%s/confirm/%d", s.Server.URL, charge.ID)
+		s.charges[charge.ID] = charge
+		writeStandInJSON(w, 201, "recurring_application_charge", charge)
+	default:
+		parts := strings.Split(strings.TrimPrefix(rest, "/"), "/")
+		id, _ := strconv.ParseInt(parts[0], 10, 64)
+		charge, ok := s.charges[id]
+		if !ok {
+			writeStandInJSON(w, 404, "errors", "Not Found")
+			return
+		}
+
+		switch {
+		case len(parts) == 1 && r.Method == "GET":
+			writeStandInJSON(w, 200, "recurring_application_charge", charge)
+		case len(parts) == 1 && r.Method == "DELETE":
+			charge.Status = StatusCancelled
+			charge.CancelledOn = time.Now().Format("2006-01-02")
+			w.Write([]byte("{}"))
+		case len(parts) == 2 && parts[1] == "activate" && r.Method == "POST":
+			if charge.Status != StatusAccepted {
+				writeStandInJSON(w, 422, "errors", map[string]string{"base": "charge is not accepted"})
+				return
+			}
+			charge.Status = StatusActive
+			charge.ActivatedOn = time.Now().Format("2006-01-02")
+			if charge.TrialDays > 0 {
+				charge.TrialEndsOn = time.Now().AddDate(0, 0, charge.TrialDays).Format("2006-01-02")
+			}
+			writeStandInJSON(w, 200, "recurring_application_charge", charge)
+		default:
+			http.NotFound(w, r)
+		}
+	}
+}
+
+func (s *BillingStandIn) confirm(w http.ResponseWriter, r *http.Request, rawID string) {
+	id, _ := strconv.ParseInt(rawID, 10, 64)
+	charge, ok := s.charges[id]
+	if !ok || charge.Status != StatusPending {
+		http.NotFound(w, r)
+		return
+	}
+
+	charge.Status = StatusAccepted
+	if r.URL.Query().Get("decline") != "" {
+		charge.Status = StatusDeclined
+	}
+
+	returnURL, err := url.Parse(charge.ReturnURL)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	q := returnURL.Query()
+	q.Set("charge_id", rawID)
+	returnURL.RawQuery = q.Encode()
+	http.Redirect(w, r, returnURL.String(), http.StatusFound)
+}
+
+func writeStandInJSON(w http.ResponseWriter, status int, key string, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{key: value})
+}
@@ -0,0 +1,201 @@
+package shopify
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// followBilling runs one request through the manager and the stand-in's
+// confirmation page, returning where the merchant ends up.
+func followBilling(t *testing.T, m *BillingManager, standIn *BillingStandIn, query string, decline bool) *url.URL {
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("GET", "/billing?"+signQuery(app.APISecret, query), nil))
+	if w.Code != http.StatusFound {
+		t.Fatalf("expected redirect to confirmation, got %d: %s", w.Code, w.Body.String())
+	}
+
+	client := standIn.Server.Client()
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	confirmation := w.Header().Get("Location")
+	if decline {
+		confirmation += "?decline=1"
+	}
+	resp, err := client.Get(confirmation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	back, _ := url.Parse(resp.Header.Get("Location"))
+	w = httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("GET", back.RequestURI(), nil))
+	if w.Code != http.StatusFound {
+		t.Fatalf("expected redirect after return, got %d: %s", w.Code, w.Body.String())
+	}
+	final, _ := url.Parse(w.Header().Get("Location"))
+	return final
+}
+
+func TestBillingManagerFlow(t *testing.T) {
+	standIn := NewBillingStandIn()
+	defer standIn.Close()
+	api := standIn.API()
+
+	activated := 0
+	m := &BillingManager{
+		App: &app,
+		Plans: []BillingPlan{
+			{Name: "Basic", Price: "10.00", TrialDays: 14},
+			{Name: "Pro", Price: "30.00", TrialDays: 14},
+		},
+		ReturnURL:   "https://app.com/billing",
+		SuccessURL:  "/admin",
+		DeclinedURL: "/plans",
+		Test:        true,
+		APIForShop: func(shop string) (*API, error) {
+			return api, nil
+		},
+		OnActivated: func(shop string, charge *RecurringApplicationCharge) {
+			activated++
+		},
+	}
+
+	final := followBilling(t, m, standIn, "shop=burnsmod.myshopify.com&plan=Basic", false)
+	if final.Path != "/admin" || final.Query().Get("shop") != "burnsmod.myshopify.com" {
+		t.Errorf("unexpected final redirect: %s", final)
+	}
+
+	basic, err := m.ActiveCharge("burnsmod.myshopify.com")
+	if err != nil || basic == nil || basic.Name != "Basic" || !basic.Test {
+		t.Fatalf("expected an active test Basic charge, got %#v (%v)", basic, err)
+	}
+
+	followBilling(t, m, standIn, "shop=burnsmod.myshopify.com&plan=Pro", false)
+
+	pro, _ := m.ActiveCharge("burnsmod.myshopify.com")
+	if pro == nil || pro.Name != "Pro" {
+		t.Fatalf("expected an active Pro charge, got %#v", pro)
+	}
+	// the stand-in only keeps the trial end date
+	if pro.TrialDays < 13 || pro.TrialDays > 14 {
+		t.Errorf("expected the remaining trial to carry over, got %d days", pro.TrialDays)
+	}
+	if old, _ := standIn.Charge(basic.ID); old.Status != StatusCancelled {
+		t.Errorf("expected the Basic charge to be cancelled, got %s", old.Status)
+	}
+	if activated != 2 {
+		t.Errorf("expected OnActivated twice, got %d", activated)
+	}
+
+	final = followBilling(t, m, standIn, "shop=burnsmod.myshopify.com&plan=Basic", true)
+	if final.Path != "/plans" {
+		t.Errorf("expected a declined charge to go to /plans, got %s", final)
+	}
+	if current, _ := m.ActiveCharge("burnsmod.myshopify.com"); current == nil || current.ID != pro.ID {
+		t.Errorf("declining a new plan should keep the current charge, got %#v", current)
+	}
+}
+
+func TestRemainingTrialDays(t *testing.T) {
+	now := time.Date(2021, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	charge := &RecurringApplicationCharge{TrialEndsOn: "2021-07-05"}
+	if days := remainingTrialDays(charge, now); days != 4 {
+		t.Errorf("expected 4 days, got %d", days)
+	}
+
+	charge.TrialEndsOn = "2021-06-05T00:00:00-04:00"
+	if days := remainingTrialDays(charge, now); days != 0 {
+		t.Errorf("expected an expired trial, got %d days", days)
+	}
+}
+
+func TestBillingManagerSubscribeToCurrentPlan(t *testing.T) {
+	standIn := NewBillingStandIn()
+	defer standIn.Close()
+	api := standIn.API()
+
+	m := &BillingManager{
+		App:        &app,
+		Plans:      []BillingPlan{{Name: "Basic", Price: "10", TrialDays: 14}},
+		ReturnURL:  "https://app.com/billing",
+		SuccessURL: "/admin",
+		APIForShop: func(shop string) (*API, error) {
+			return api, nil
+		},
+	}
+
+	followBilling(t, m, standIn, "shop=burnsmod.myshopify.com&plan=Basic", false)
+	current, _ := m.ActiveCharge("burnsmod.myshopify.com")
+
+	charge, err := m.Subscribe("burnsmod.myshopify.com", "Basic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current == nil || charge.ID != current.ID || charge.Status != StatusActive {
+		t.Errorf("expected the active charge %#v, got %#v", current, charge)
+	}
+	if _, ok := standIn.Charge(current.ID + 1); ok {
+		t.Errorf("expected no second charge")
+	}
+
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("GET", "/billing?"+signQuery(app.APISecret, "shop=burnsmod.myshopify.com&plan=Basic"), nil))
+	if location := w.Header().Get("Location"); location != "/admin?shop=burnsmod.myshopify.com" {
+		t.Errorf("expected a redirect to the success page, got %s", location)
+	}
+}
+
+func TestBillingManagerVerifiesShop(t *testing.T) {
+	standIn := NewBillingStandIn()
+	defer standIn.Close()
+	api := standIn.API()
+
+	m := &BillingManager{
+		App:        &app,
+		Plans:      []BillingPlan{{Name: "Basic", Price: "10.00"}},
+		ReturnURL:  "https://app.com/billing",
+		SuccessURL: "/admin",
+		APIForShop: func(shop string) (*API, error) {
+			return api, nil
+		},
+	}
+
+	rejected := map[string]int{
+		"/billing?shop=burnsmod.myshopify.com&plan=Basic":                          http.StatusUnauthorized,
+		"/billing?shop=burnsmod.myshopify.com&charge_id=1":                         http.StatusUnauthorized,
+		"/billing?" + signQuery(app.APISecret, "shop=evil.com&plan=Basic"):         http.StatusBadRequest,
+		"/billing?" + signQuery("wrong", "shop=burnsmod.myshopify.com&plan=Basic"): http.StatusUnauthorized,
+	}
+	for target, expected := range rejected {
+		w := httptest.NewRecorder()
+		m.ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+		if w.Code != expected {
+			t.Errorf("%s: expected %d, got %d", target, expected, w.Code)
+		}
+	}
+	if _, ok := standIn.Charge(1); ok {
+		t.Errorf("expected no charge to be created")
+	}
+
+	// the signed return URL can't be moved to another shop
+	charge, err := m.Subscribe("burnsmod.myshopify.com", "Basic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	returnURL, _ := url.Parse(charge.ReturnURL)
+	q := returnURL.Query()
+	q.Set("shop", "other.myshopify.com")
+	q.Set("charge_id", fmt.Sprint(charge.ID))
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("GET", "/billing?"+q.Encode(), nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected a tampered return to be refused, got %d", w.Code)
+	}
+}
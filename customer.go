@@ -50,15 +50,56 @@ type Customer struct {
 	api *API
 }
 
-func (api *API) Customers() ([]Customer, error) {
-	res, status, err := api.request("/admin/customers.json", "GET", nil, nil)
+type CustomerOptions struct {
+	IDs          string `url:"ids,omitempty"`
+	Limit        int    `url:"limit,omitempty"`
+	SinceID      int64  `url:"since_id,omitempty"`
+	CreatedAtMin string `url:"created_at_min,omitempty"`
+	CreatedAtMax string `url:"created_at_max,omitempty"`
+	UpdatedAtMin string `url:"updated_at_min,omitempty"`
+	UpdatedAtMax string `url:"updated_at_max,omitempty"`
+	Fields       string `url:"fields,omitempty"`
+}
+
+// CustomerSearchOptions takes a Shopify search query, which CustomerQuery
+// can build.
+type CustomerSearchOptions struct {
+	Query  string `url:"query,omitempty"`
+	Order  string `url:"order,omitempty"`
+	Limit  int    `url:"limit,omitempty"`
+	Fields string `url:"fields,omitempty"`
+}
+
+func (api *API) Customers() ([]Customer, *Pages, error) {
+	return api.CustomersWithOptions(&CustomerOptions{})
+}
+
+func (api *API) CustomersWithOptions(options *CustomerOptions) ([]Customer, *Pages, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("BASE_PATH/customers.json?%v", qs)
+	return api.processCustomersResponse(api.requestWithPagination(endpoint, "GET", nil, nil))
+}
 
+func (api *API) SearchCustomers(options *CustomerSearchOptions) ([]Customer, *Pages, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("BASE_PATH/customers/search.json?%v", qs)
+	return api.processCustomersResponse(api.requestWithPagination(endpoint, "GET", nil, nil))
+}
+
+func (api *API) CustomersFromPages(pages *Pages) ([]Customer, *Pages, error) {
+	if pages.HasNextPage() {
+		return api.processCustomersResponse(api.getNextPage(pages))
+	}
+	return nil, &Pages{}, fmt.Errorf("No next page")
+}
+
+func (api *API) processCustomersResponse(res *bytes.Buffer, status int, pages *Pages, err error) ([]Customer, *Pages, error) {
 	if err != nil {
-		return nil, err
+		return nil, pages, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, pages, fmt.Errorf("Status returned: %d", status)
 	}
 
 	r := &map[string][]Customer{}
@@ -67,18 +108,18 @@ func (api *API) Customers() ([]Customer, error) {
 	result := (*r)["customers"]
 
 	if err != nil {
-		return nil, err
+		return nil, pages, err
 	}
 
-	for _, v := range result {
-		v.api = api
+	for i := range result {
+		result[i].api = api
 	}
 
-	return result, nil
+	return result, pages, nil
 }
 
 func (api *API) Customer(id int64) (*Customer, error) {
-	endpoint := fmt.Sprintf("/admin/customers/%d.json", id)
+	endpoint := fmt.Sprintf("BASE_PATH/customers/%d.json", id)
 
 	res, status, err := api.request(endpoint, "GET", nil, nil)
 
@@ -109,12 +150,12 @@ func (api *API) NewCustomer() *Customer {
 }
 
 func (obj *Customer) Save() error {
-	endpoint := fmt.Sprintf("/admin/customers/%d.json", obj.Id)
+	endpoint := fmt.Sprintf("BASE_PATH/customers/%d.json", obj.Id)
 	method := "PUT"
-	expectedStatus := 201
+	expectedStatus := 200
 
 	if obj.Id == 0 {
-		endpoint = fmt.Sprintf("/admin/customers.json")
+		endpoint = fmt.Sprintf("BASE_PATH/customers.json")
 		method = "POST"
 		expectedStatus = 201
 	}
@@ -152,7 +193,91 @@ func (obj *Customer) Save() error {
 		return err
 	}
 
+	api := obj.api
 	*obj = r["customer"]
+	obj.api = api
 
 	return nil
 }
+
+// CustomerOrders lists a customer's orders. Like OrdersWithOptions, only
+// open orders are returned unless Status is set.
+func (api *API) CustomerOrders(customerID int64, options *OrderOptions) ([]Order, *Pages, error) {
+	var fields []string
+	if options != nil {
+		fields = options.Fields
+		if err := checkOrderFields(fields); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("BASE_PATH/customers/%d/orders.json?%v", customerID, qs)
+
+	res, status, pages, err := api.requestWithPagination(endpoint, "GET", nil, nil)
+	return api.processOrdersResponse(fields, res, status, pages, err)
+}
+
+func (obj *Customer) Orders(options *OrderOptions) ([]Order, *Pages, error) {
+	return obj.api.CustomerOrders(obj.Id, options)
+}
+
+// CustomerInvite customizes the account invite email. Empty fields use the
+// shop's defaults.
+type CustomerInvite struct {
+	To            string   `json:"to,omitempty"`
+	From          string   `json:"from,omitempty"`
+	Bcc           []string `json:"bcc,omitempty"`
+	Subject       string   `json:"subject,omitempty"`
+	CustomMessage string   `json:"custom_message,omitempty"`
+}
+
+// AccountActivationURL returns a one-time URL for the customer to activate
+// their account. Creating a new URL expires the previous one.
+func (obj *Customer) AccountActivationURL() (string, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/customers/%d/account_activation_url.json", obj.Id)
+
+	r := map[string]string{}
+	err := obj.post(endpoint, map[string]interface{}{}, &r)
+	if err != nil {
+		return "", err
+	}
+
+	return r["account_activation_url"], nil
+}
+
+// SendInvite emails the customer an invite to create their account.
+func (obj *Customer) SendInvite(invite CustomerInvite) error {
+	endpoint := fmt.Sprintf("BASE_PATH/customers/%d/send_invite.json", obj.Id)
+	return obj.post(endpoint, map[string]CustomerInvite{"customer_invite": invite}, nil)
+}
+
+func (obj *Customer) post(endpoint string, body interface{}, result interface{}) error {
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	res, status, err := obj.api.request(endpoint, "POST", nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != 200 && status != 201 {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return fmt.Errorf("Status %d: %v", status, r.Errors)
+		}
+
+		return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(res).Decode(result)
+}
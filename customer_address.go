@@ -0,0 +1,187 @@
+package shopify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+type CustomerAddress struct {
+	ID           int64  `json:"id,omitempty"`
+	CustomerID   int64  `json:"customer_id,omitempty"`
+	FirstName    string `json:"first_name,omitempty"`
+	LastName     string `json:"last_name,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Company      string `json:"company,omitempty"`
+	Address1     string `json:"address1,omitempty"`
+	Address2     string `json:"address2,omitempty"`
+	City         string `json:"city,omitempty"`
+	Province     string `json:"province,omitempty"`
+	ProvinceCode string `json:"province_code,omitempty"`
+	Country      string `json:"country,omitempty"`
+	CountryCode  string `json:"country_code,omitempty"`
+	CountryName  string `json:"country_name,omitempty"`
+	Zip          string `json:"zip,omitempty"`
+	Phone        string `json:"phone,omitempty"`
+	Default      bool   `json:"default,omitempty"`
+
+	api *API
+}
+
+func (api *API) CustomerAddresses(customerID int64) ([]*CustomerAddress, *Pages, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/customers/%d/addresses.json", customerID)
+	return api.processCustomerAddressesResponse(api.requestWithPagination(endpoint, "GET", nil, nil))
+}
+
+func (api *API) CustomerAddressesFromPages(pages *Pages) ([]*CustomerAddress, *Pages, error) {
+	if pages.HasNextPage() {
+		return api.processCustomerAddressesResponse(api.getNextPage(pages))
+	}
+	return nil, &Pages{}, fmt.Errorf("No next page")
+}
+
+func (api *API) processCustomerAddressesResponse(res *bytes.Buffer, status int, pages *Pages, err error) ([]*CustomerAddress, *Pages, error) {
+	if err != nil {
+		return nil, pages, err
+	}
+
+	if status != 200 {
+		return nil, pages, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := &map[string][]*CustomerAddress{}
+	err = json.NewDecoder(res).Decode(r)
+	if err != nil {
+		return nil, pages, err
+	}
+
+	result := (*r)["addresses"]
+	for _, v := range result {
+		v.api = api
+	}
+
+	return result, pages, nil
+}
+
+func (obj *Customer) CustomerAddresses() ([]*CustomerAddress, *Pages, error) {
+	return obj.api.CustomerAddresses(obj.Id)
+}
+
+func (api *API) CustomerAddress(customerID int64, id int64) (*CustomerAddress, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/customers/%d/addresses/%d.json", customerID, id)
+
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := map[string]CustomerAddress{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	result := r["customer_address"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.api = api
+
+	return &result, nil
+}
+
+func (api *API) NewCustomerAddress(customerID int64) *CustomerAddress {
+	return &CustomerAddress{CustomerID: customerID, api: api}
+}
+
+func (obj *CustomerAddress) Save() error {
+	endpoint := fmt.Sprintf("BASE_PATH/customers/%d/addresses/%d.json", obj.CustomerID, obj.ID)
+	method := "PUT"
+	expectedStatus := 200
+
+	if obj.ID == 0 {
+		endpoint = fmt.Sprintf("BASE_PATH/customers/%d/addresses.json", obj.CustomerID)
+		method = "POST"
+		expectedStatus = 201
+	}
+
+	body := map[string]*CustomerAddress{}
+	body["address"] = obj
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	return obj.send(endpoint, method, expectedStatus, buf)
+}
+
+// SetDefault makes this the customer's default address.
+func (obj *CustomerAddress) SetDefault() error {
+	endpoint := fmt.Sprintf("BASE_PATH/customers/%d/addresses/%d/default.json", obj.CustomerID, obj.ID)
+	// send an empty body, as a request without one would be cached
+	return obj.send(endpoint, "PUT", 200, bytes.NewBufferString("{}"))
+}
+
+func (obj *CustomerAddress) send(endpoint string, method string, expectedStatus int, buf io.Reader) error {
+	res, status, err := obj.api.request(endpoint, method, nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return fmt.Errorf("Status %d: %v", status, r.Errors)
+		}
+
+		return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+	}
+
+	r := map[string]CustomerAddress{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	api := obj.api
+	*obj = r["customer_address"]
+	obj.api = api
+
+	return nil
+}
+
+// Delete the address. Shopify won't delete a customer's default address.
+func (obj *CustomerAddress) Delete() error {
+	endpoint := fmt.Sprintf("BASE_PATH/customers/%d/addresses/%d.json", obj.CustomerID, obj.ID)
+	method := "DELETE"
+	expectedStatus := 200
+
+	res, status, err := obj.api.request(endpoint, method, nil, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return fmt.Errorf("Status %d: %v", status, r.Errors)
+		}
+
+		return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+	}
+
+	return nil
+}
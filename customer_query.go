@@ -0,0 +1,110 @@
+package shopify
+
+import (
+	"fmt"
+	"strings"
+)
+
+// QueryComparison compares a numeric or date field in a search query.
+type QueryComparison string
+
+const (
+	QueryEquals      QueryComparison = ""
+	QueryGreaterThan QueryComparison = ">"
+	QueryAtLeast     QueryComparison = ">="
+	QueryLessThan    QueryComparison = "<"
+	QueryAtMost      QueryComparison = "<="
+)
+
+// CustomerQuery builds a customer search query. Terms are combined with
+// AND; for example
+//
+//	NewCustomerQuery().Tag("wholesale").OrdersCount(QueryAtLeast, 5).String()
+//
+// gives `tag:wholesale orders_count:>=5`.
+type CustomerQuery struct {
+	terms []string
+}
+
+func NewCustomerQuery() *CustomerQuery {
+	return &CustomerQuery{}
+}
+
+func (q *CustomerQuery) Email(email string) *CustomerQuery {
+	return q.Field("email", email)
+}
+
+func (q *CustomerQuery) Tag(tag string) *CustomerQuery {
+	return q.Field("tag", tag)
+}
+
+func (q *CustomerQuery) FirstName(name string) *CustomerQuery {
+	return q.Field("first_name", name)
+}
+
+func (q *CustomerQuery) LastName(name string) *CustomerQuery {
+	return q.Field("last_name", name)
+}
+
+func (q *CustomerQuery) Phone(phone string) *CustomerQuery {
+	return q.Field("phone", phone)
+}
+
+func (q *CustomerQuery) Country(country string) *CustomerQuery {
+	return q.Field("country", country)
+}
+
+// State matches the account state: disabled, invited, enabled or declined.
+func (q *CustomerQuery) State(state string) *CustomerQuery {
+	return q.Field("state", state)
+}
+
+func (q *CustomerQuery) AcceptsMarketing(accepts bool) *CustomerQuery {
+	return q.Field("accepts_marketing", fmt.Sprint(accepts))
+}
+
+func (q *CustomerQuery) OrdersCount(comparison QueryComparison, count int64) *CustomerQuery {
+	return q.Compare("orders_count", comparison, fmt.Sprint(count))
+}
+
+func (q *CustomerQuery) TotalSpent(comparison QueryComparison, amount string) *CustomerQuery {
+	return q.Compare("total_spent", comparison, amount)
+}
+
+// CreatedAt compares against a date like 2021-07-01.
+func (q *CustomerQuery) CreatedAt(comparison QueryComparison, date string) *CustomerQuery {
+	return q.Compare("created_at", comparison, date)
+}
+
+func (q *CustomerQuery) UpdatedAt(comparison QueryComparison, date string) *CustomerQuery {
+	return q.Compare("updated_at", comparison, date)
+}
+
+// Field matches field against value, quoting the value when needed.
+func (q *CustomerQuery) Field(field string, value string) *CustomerQuery {
+	q.terms = append(q.terms, field+":"+quoteQueryValue(value))
+	return q
+}
+
+func (q *CustomerQuery) Compare(field string, comparison QueryComparison, value string) *CustomerQuery {
+	q.terms = append(q.terms, field+":"+string(comparison)+quoteQueryValue(value))
+	return q
+}
+
+// Text adds free text, matched against the customer's name, email and
+// other fields.
+func (q *CustomerQuery) Text(text string) *CustomerQuery {
+	q.terms = append(q.terms, quoteQueryValue(text))
+	return q
+}
+
+func (q *CustomerQuery) String() string {
+	return strings.Join(q.terms, " ")
+}
+
+func quoteQueryValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t:\"'()\\") {
+		return value
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
@@ -7,6 +7,8 @@ import (
 
 	"fmt"
 
+	"strconv"
+
 	"time"
 )
 
@@ -15,17 +17,17 @@ type CustomerSavedSearch struct {
 
 	Id int64 `json:"id"`
 
-	Name time.Time `json:"name"`
+	Name string `json:"name"`
 
 	UpdatedAt time.Time `json:"updated_at"`
 
-	Query time.Time `json:"query"`
+	Query string `json:"query"`
 
 	api *API
 }
 
 func (api *API) CustomerSavedSearches() ([]CustomerSavedSearch, error) {
-	res, status, err := api.request("/admin/customer_saved_searches.json", "GET", nil, nil)
+	res, status, err := api.request("BASE_PATH/customer_saved_searches.json", "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
@@ -38,23 +40,21 @@ func (api *API) CustomerSavedSearches() ([]CustomerSavedSearch, error) {
 	r := &map[string][]CustomerSavedSearch{}
 	err = json.NewDecoder(res).Decode(r)
 
-	fmt.Printf("things are: %v\n\n", *r)
-
 	result := (*r)["customer_saved_searches"]
 
 	if err != nil {
 		return nil, err
 	}
 
-	for _, v := range result {
-		v.api = api
+	for i := range result {
+		result[i].api = api
 	}
 
 	return result, nil
 }
 
 func (api *API) CustomerSavedSearch(id int64) (*CustomerSavedSearch, error) {
-	endpoint := fmt.Sprintf("/admin/customer_saved_searches/%d.json", id)
+	endpoint := fmt.Sprintf("BASE_PATH/customer_saved_searches/%d.json", id)
 
 	res, status, err := api.request(endpoint, "GET", nil, nil)
 
@@ -69,8 +69,6 @@ func (api *API) CustomerSavedSearch(id int64) (*CustomerSavedSearch, error) {
 	r := map[string]CustomerSavedSearch{}
 	err = json.NewDecoder(res).Decode(&r)
 
-	fmt.Printf("things are: %v\n\n", r)
-
 	result := r["customer_saved_search"]
 
 	if err != nil {
@@ -86,13 +84,19 @@ func (api *API) NewCustomerSavedSearch() *CustomerSavedSearch {
 	return &CustomerSavedSearch{api: api}
 }
 
+// NewCustomerSavedSearchFromQuery starts a saved search for the customers
+// matching query.
+func (api *API) NewCustomerSavedSearchFromQuery(name string, query *CustomerQuery) *CustomerSavedSearch {
+	return &CustomerSavedSearch{Name: name, Query: query.String(), api: api}
+}
+
 func (obj *CustomerSavedSearch) Save() error {
-	endpoint := fmt.Sprintf("/admin/customer_saved_searches/%d.json", obj.Id)
+	endpoint := fmt.Sprintf("BASE_PATH/customer_saved_searches/%d.json", obj.Id)
 	method := "PUT"
-	expectedStatus := 201
+	expectedStatus := 200
 
 	if obj.Id == 0 {
-		endpoint = fmt.Sprintf("/admin/customer_saved_searches.json")
+		endpoint = fmt.Sprintf("BASE_PATH/customer_saved_searches.json")
 		method = "POST"
 		expectedStatus = 201
 	}
@@ -130,11 +134,65 @@ func (obj *CustomerSavedSearch) Save() error {
 		return err
 	}
 
-	fmt.Printf("things are: %v\n\n", r)
-
+	api := obj.api
 	*obj = r["customer_saved_search"]
-
-	fmt.Printf("things are: %v\n\n", res)
+	obj.api = api
 
 	return nil
 }
+
+type CustomerSavedSearchCustomersOptions struct {
+	Order  string `url:"order,omitempty"`
+	Limit  int    `url:"limit,omitempty"`
+	Fields string `url:"fields,omitempty"`
+}
+
+// Customers returns the first page of customers matching the saved search.
+// Use CustomersFromPages for the rest.
+func (obj *CustomerSavedSearch) Customers(options *CustomerSavedSearchCustomersOptions) ([]Customer, *Pages, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("BASE_PATH/customer_saved_searches/%d/customers.json?%v", obj.Id, qs)
+	return obj.api.processCustomersResponse(obj.api.requestWithPagination(endpoint, "GET", nil, nil))
+}
+
+// CustomersCount counts the customers matching the saved search. The REST
+// API has no count endpoint for saved searches or customer queries, so this
+// pages through the matching ids, 250 per request: a segment of 100,000
+// customers takes 400 requests and draws on the shop's rate limit the
+// whole time. Cache the result rather than calling it per page view.
+func (obj *CustomerSavedSearch) CustomersCount() (int, error) {
+	count := 0
+
+	customers, pages, err := obj.Customers(&CustomerSavedSearchCustomersOptions{Limit: 250, Fields: "id"})
+	for {
+		if err != nil {
+			return 0, err
+		}
+		count += len(customers)
+		if !pages.HasNextPage() {
+			return count, nil
+		}
+		customers, pages, err = obj.api.CustomersFromPages(pages)
+	}
+}
+
+func (api *API) CustomerSavedSearchesCount() (int, error) {
+	res, status, err := api.request("BASE_PATH/customer_saved_searches/count.json", "GET", nil, nil)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if status != 200 {
+		return 0, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := map[string]interface{}{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	result, _ := strconv.Atoi(fmt.Sprintf("%v", r["count"]))
+	if err != nil {
+		return 0, err
+	}
+	return result, nil
+}
@@ -0,0 +1,61 @@
+package shopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCustomerSavedSearchFromQuery(t *testing.T) {
+	var sent map[string]map[string]interface{}
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/admin/api/2021-07/customer_saved_searches.json" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.WriteHeader(201)
+		w.Write([]byte(`{"customer_saved_search":{"id":789629109,"name":"Big spenders","query":"total_spent:>500","created_at":"2021-07-01T12:00:00-04:00"}}`))
+	}))
+	defer ts.Close()
+
+	query := NewCustomerQuery().TotalSpent(QueryGreaterThan, "500")
+	search := newTestAPI(ts).NewCustomerSavedSearchFromQuery("Big spenders", query)
+	if err := search.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if sent["customer_saved_search"]["query"] != "total_spent:>500" {
+		t.Errorf("unexpected body %v", sent)
+	}
+	if search.Id != 789629109 || search.Name != "Big spenders" || search.api == nil {
+		t.Errorf("unexpected search %+v", search)
+	}
+}
+
+func TestCustomerSavedSearchCustomersCount(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page_info") == "" {
+			if r.URL.Query().Get("fields") != "id" {
+				t.Errorf("expected only ids, got %s", r.URL.RawQuery)
+			}
+			w.Header().Set("Link", `<`+ts.URL+`/admin/api/2021-07/customer_saved_searches/789629109/customers.json?page_info=abc>; rel="next"`)
+			w.Write([]byte(`{"customers":[{"id":1},{"id":2}]}`))
+			return
+		}
+		w.Write([]byte(`{"customers":[{"id":3}]}`))
+	}))
+	defer ts.Close()
+
+	search := newTestAPI(ts).NewCustomerSavedSearch()
+	search.Id = 789629109
+
+	count, err := search.CustomersCount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("expected 3 customers, got %d", count)
+	}
+}
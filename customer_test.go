@@ -0,0 +1,217 @@
+package shopify
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCustomerQuery(t *testing.T) {
+	q := NewCustomerQuery().
+		Tag("wholesale").
+		Email("bob@example.com").
+		Country("United States").
+		OrdersCount(QueryAtLeast, 5).
+		TotalSpent(QueryGreaterThan, "100.00")
+
+	expected := `tag:wholesale email:bob@example.com country:"United States" orders_count:>=5 total_spent:>100.00`
+	if q.String() != expected {
+		t.Errorf("expected %s, got %s", expected, q)
+	}
+}
+
+func TestSearchCustomers(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/api/2021-07/customers/search.json" || r.URL.Query().Get("query") != "tag:vip" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"customers":[{"id":207119551,"email":"bob@example.com"}]}`))
+	}))
+	defer ts.Close()
+
+	customers, _, err := newTestAPI(ts).SearchCustomers(&CustomerSearchOptions{Query: NewCustomerQuery().Tag("vip").String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(customers) != 1 || customers[0].Id != 207119551 || customers[0].api == nil {
+		t.Errorf("unexpected customers %+v", customers)
+	}
+}
+
+func TestCustomerInvite(t *testing.T) {
+	var sent map[string]map[string]interface{}
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin/api/2021-07/customers/207119551/account_activation_url.json":
+			w.Write([]byte(`{"account_activation_url":"https://shop.example.com/account/activate/207119551/abc"}`))
+		case "/admin/api/2021-07/customers/207119551/send_invite.json":
+			json.NewDecoder(r.Body).Decode(&sent)
+			w.Write([]byte(`{"customer_invite":{"subject":"Welcome"}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	customer := newTestAPI(ts).NewCustomer()
+	customer.Id = 207119551
+
+	url, err := customer.AccountActivationURL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if url != "https://shop.example.com/account/activate/207119551/abc" {
+		t.Errorf("unexpected url %s", url)
+	}
+
+	err = customer.SendInvite(CustomerInvite{Subject: "Welcome", CustomMessage: "Your wholesale account is ready"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent["customer_invite"]["subject"] != "Welcome" || sent["customer_invite"]["custom_message"] != "Your wholesale account is ready" {
+		t.Errorf("unexpected invite %v", sent)
+	}
+}
+
+func TestCustomerAddressSetDefault(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/admin/api/2021-07/customers/207119551/addresses/1053317291/default.json" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if body, _ := ioutil.ReadAll(r.Body); strings.TrimSpace(string(body)) != "{}" {
+			t.Errorf("expected an empty JSON body, got %q", body)
+		}
+		w.Write([]byte(`{"customer_address":{"id":1053317291,"customer_id":207119551,"default":true}}`))
+	}))
+	defer ts.Close()
+
+	address := newTestAPI(ts).NewCustomerAddress(207119551)
+	address.ID = 1053317291
+	if err := address.SetDefault(); err != nil {
+		t.Fatal(err)
+	}
+	if !address.Default || address.api == nil {
+		t.Errorf("unexpected address %+v", address)
+	}
+}
+
+func TestCustomerAddressSaveAndDelete(t *testing.T) {
+	var sent map[string]map[string]interface{}
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /admin/api/2021-07/customers/207119551/addresses.json":
+			json.NewDecoder(r.Body).Decode(&sent)
+			w.WriteHeader(201)
+			w.Write([]byte(`{"customer_address":{"id":1053317291,"customer_id":207119551,"address1":"1 Rue des Carrieres","city":"Suite 1234","country":"Canada"}}`))
+		case "PUT /admin/api/2021-07/customers/207119551/addresses/1053317291.json":
+			json.NewDecoder(r.Body).Decode(&sent)
+			w.Write([]byte(`{"customer_address":{"id":1053317291,"customer_id":207119551,"address1":"1 Rue des Carrieres","zip":"90210"}}`))
+		case "DELETE /admin/api/2021-07/customers/207119551/addresses/1053317291.json":
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	address := newTestAPI(ts).NewCustomerAddress(207119551)
+	address.Address1 = "1 Rue des Carrieres"
+	address.Country = "Canada"
+	if err := address.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if sent["address"]["address1"] != "1 Rue des Carrieres" || sent["address"]["country"] != "Canada" {
+		t.Errorf("unexpected create body %v", sent)
+	}
+	if address.ID != 1053317291 || address.api == nil {
+		t.Errorf("unexpected address %+v", address)
+	}
+
+	address.Zip = "90210"
+	if err := address.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if sent["address"]["zip"] != "90210" || sent["address"]["id"] != float64(1053317291) {
+		t.Errorf("unexpected update body %v", sent)
+	}
+	if address.Zip != "90210" || address.api == nil {
+		t.Errorf("unexpected address %+v", address)
+	}
+
+	if err := address.Delete(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCustomerAddressDeleteDefault(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(422)
+		w.Write([]byte(`{"errors":{"base":["Cannot delete the customer's default address"]}}`))
+	}))
+	defer ts.Close()
+
+	address := newTestAPI(ts).NewCustomerAddress(207119551)
+	address.ID = 1053317291
+	if err := address.Delete(); err == nil {
+		t.Errorf("expected an error deleting the default address")
+	}
+}
+
+func TestCustomerOrders(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/api/2021-07/customers/207119551/orders.json" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		if q := r.URL.Query(); q.Get("status") != "any" || q.Get("fields") != "id,total_price" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"orders":[{"id":450789469,"total_price":"598.94"},{"id":450789470,"total_price":"10.00"}]}`))
+	}))
+	defer ts.Close()
+
+	customer := newTestAPI(ts).NewCustomer()
+	customer.Id = 207119551
+
+	orders, _, err := customer.Orders(&OrderOptions{Status: OrderStatusAny, Fields: []string{"id", "total_price"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orders) != 2 || orders[0].TotalPrice != "598.94" || !orders[0].HasField("total_price") {
+		t.Errorf("unexpected orders %+v", orders)
+	}
+
+	if _, _, err := customer.Orders(&OrderOptions{Fields: []string{"totl_price"}}); err == nil {
+		t.Errorf("expected an error for an unknown field")
+	}
+}
+
+func TestCustomerSaveUsesVersionedPath(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /admin/api/2021-07/customers.json":
+			w.WriteHeader(201)
+			w.Write([]byte(`{"customer":{"id":207119551,"email":"steve.lastnameson@example.com"}}`))
+		case "PUT /admin/api/2021-07/customers/207119551.json":
+			w.Write([]byte(`{"customer":{"id":207119551,"email":"steve@example.com"}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	customer := newTestAPI(ts).NewCustomer()
+	customer.Email = "steve.lastnameson@example.com"
+	if err := customer.Save(); err != nil {
+		t.Fatal(err)
+	}
+	customer.Email = "steve@example.com"
+	if err := customer.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if customer.Id != 207119551 || customer.Email != "steve@example.com" {
+		t.Errorf("unexpected customer %+v", customer)
+	}
+}
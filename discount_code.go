@@ -0,0 +1,340 @@
+package shopify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// MAX_DISCOUNT_CODE_BATCH is the most codes one batch job can create.
+const MAX_DISCOUNT_CODE_BATCH = 100
+
+const (
+	DiscountCodeCreationQueued    = "queued"
+	DiscountCodeCreationRunning   = "running"
+	DiscountCodeCreationCompleted = "completed"
+)
+
+type DiscountCode struct {
+	ID          int64  `json:"id,omitempty"`
+	PriceRuleID int64  `json:"price_rule_id,omitempty"`
+	Code        string `json:"code,omitempty"`
+	UsageCount  int64  `json:"usage_count,omitempty"`
+	CreatedAt   string `json:"created_at,omitempty"`
+	UpdatedAt   string `json:"updated_at,omitempty"`
+
+	// Errors is only set on codes returned by a batch job, for codes that
+	// couldn't be created.
+	Errors map[string]interface{} `json:"errors,omitempty"`
+
+	api *API
+}
+
+// DiscountCodeCreation is a batch job creating discount codes.
+type DiscountCodeCreation struct {
+	ID            int64  `json:"id,omitempty"`
+	PriceRuleID   int64  `json:"price_rule_id,omitempty"`
+	Status        string `json:"status,omitempty"`
+	CodesCount    int64  `json:"codes_count,omitempty"`
+	ImportedCount int64  `json:"imported_count,omitempty"`
+	FailedCount   int64  `json:"failed_count,omitempty"`
+	StartedAt     string `json:"started_at,omitempty"`
+	CompletedAt   string `json:"completed_at,omitempty"`
+	CreatedAt     string `json:"created_at,omitempty"`
+	UpdatedAt     string `json:"updated_at,omitempty"`
+
+	api *API
+}
+
+func (api *API) DiscountCodes(priceRuleID int64) ([]*DiscountCode, *Pages, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/price_rules/%d/discount_codes.json", priceRuleID)
+	return api.processDiscountCodesResponse(api.requestWithPagination(endpoint, "GET", nil, nil))
+}
+
+func (api *API) DiscountCodesFromPages(pages *Pages) ([]*DiscountCode, *Pages, error) {
+	if pages.HasNextPage() {
+		return api.processDiscountCodesResponse(api.getNextPage(pages))
+	}
+	return nil, &Pages{}, fmt.Errorf("No next page")
+}
+
+func (api *API) processDiscountCodesResponse(res *bytes.Buffer, status int, pages *Pages, err error) ([]*DiscountCode, *Pages, error) {
+	if err != nil {
+		return nil, pages, err
+	}
+
+	if status != 200 {
+		return nil, pages, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := &map[string][]*DiscountCode{}
+	err = json.NewDecoder(res).Decode(r)
+	if err != nil {
+		return nil, pages, err
+	}
+
+	result := (*r)["discount_codes"]
+	for _, v := range result {
+		v.api = api
+	}
+
+	return result, pages, nil
+}
+
+func (obj *PriceRule) DiscountCodes() ([]*DiscountCode, *Pages, error) {
+	return obj.api.DiscountCodes(obj.ID)
+}
+
+func (api *API) DiscountCode(priceRuleID int64, id int64) (*DiscountCode, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/price_rules/%d/discount_codes/%d.json", priceRuleID, id)
+	return api.getDiscountCode(endpoint)
+}
+
+// LookupDiscountCode finds a discount code by its code, whichever price
+// rule it belongs to.
+func (api *API) LookupDiscountCode(code string) (*DiscountCode, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/discount_codes/lookup.json?code=%s", url.QueryEscape(code))
+	return api.getDiscountCode(endpoint)
+}
+
+func (api *API) getDiscountCode(endpoint string) (*DiscountCode, error) {
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := map[string]DiscountCode{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	result := r["discount_code"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.api = api
+
+	return &result, nil
+}
+
+func (api *API) NewDiscountCode(priceRuleID int64) *DiscountCode {
+	return &DiscountCode{PriceRuleID: priceRuleID, api: api}
+}
+
+func (obj *DiscountCode) Save() error {
+	endpoint := fmt.Sprintf("BASE_PATH/price_rules/%d/discount_codes/%d.json", obj.PriceRuleID, obj.ID)
+	method := "PUT"
+	expectedStatus := 200
+
+	if obj.ID == 0 {
+		endpoint = fmt.Sprintf("BASE_PATH/price_rules/%d/discount_codes.json", obj.PriceRuleID)
+		method = "POST"
+		expectedStatus = 201
+	}
+
+	body := map[string]*DiscountCode{}
+	body["discount_code"] = obj
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	res, status, err := obj.api.request(endpoint, method, nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return fmt.Errorf("Status %d: %v", status, r.Errors)
+		}
+
+		return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+	}
+
+	r := map[string]DiscountCode{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	api := obj.api
+	*obj = r["discount_code"]
+	obj.api = api
+
+	return nil
+}
+
+func (obj *DiscountCode) Delete() error {
+	endpoint := fmt.Sprintf("BASE_PATH/price_rules/%d/discount_codes/%d.json", obj.PriceRuleID, obj.ID)
+	method := "DELETE"
+	expectedStatus := 204
+
+	res, status, err := obj.api.request(endpoint, method, nil, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return fmt.Errorf("Status %d: %v", status, r.Errors)
+		}
+
+		return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+	}
+
+	return nil
+}
+
+// CreateDiscountCodes starts a batch job creating up to
+// MAX_DISCOUNT_CODE_BATCH codes under a price rule. Wait for it to finish,
+// then check DiscountCodes for codes that failed.
+func (api *API) CreateDiscountCodes(priceRuleID int64, codes []string) (*DiscountCodeCreation, error) {
+	if len(codes) > MAX_DISCOUNT_CODE_BATCH {
+		return nil, fmt.Errorf("Can't create more than %d discount codes in a batch", MAX_DISCOUNT_CODE_BATCH)
+	}
+
+	endpoint := fmt.Sprintf("BASE_PATH/price_rules/%d/batch.json", priceRuleID)
+	method := "POST"
+	expectedStatus := 201
+
+	list := make([]map[string]string, len(codes))
+	for i, code := range codes {
+		list[i] = map[string]string{"code": code}
+	}
+	body := map[string]interface{}{"discount_codes": list}
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, status, err := api.request(endpoint, method, nil, buf)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return nil, fmt.Errorf("Status %d: %v", status, r.Errors)
+		}
+
+		return nil, fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+	}
+
+	r := map[string]DiscountCodeCreation{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := r["discount_code_creation"]
+	result.api = api
+
+	return &result, nil
+}
+
+// CreateDiscountCodeBatches creates any number of codes under a price rule,
+// in batch jobs of MAX_DISCOUNT_CODE_BATCH. Shopify runs one job per shop at
+// a time, so each job is waited on, polling every interval for up to
+// timeout, before the next starts. The finished jobs are returned so failed
+// codes can be checked, including those created before an error.
+func (api *API) CreateDiscountCodeBatches(priceRuleID int64, codes []string, interval time.Duration, timeout time.Duration) ([]*DiscountCodeCreation, error) {
+	jobs := []*DiscountCodeCreation{}
+	for start := 0; start < len(codes); start += MAX_DISCOUNT_CODE_BATCH {
+		end := start + MAX_DISCOUNT_CODE_BATCH
+		if end > len(codes) {
+			end = len(codes)
+		}
+
+		job, err := api.CreateDiscountCodes(priceRuleID, codes[start:end])
+		if err != nil {
+			return jobs, err
+		}
+		jobs = append(jobs, job)
+
+		if err := job.Wait(interval, timeout); err != nil {
+			return jobs, err
+		}
+	}
+	return jobs, nil
+}
+
+func (obj *DiscountCodeCreation) Done() bool {
+	return obj.Status == DiscountCodeCreationCompleted
+}
+
+// Refresh reloads the job's status and counts.
+func (obj *DiscountCodeCreation) Refresh() error {
+	endpoint := fmt.Sprintf("BASE_PATH/price_rules/%d/batch/%d.json", obj.PriceRuleID, obj.ID)
+
+	res, status, err := obj.api.request(endpoint, "GET", noCache, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if status != 200 {
+		return fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := map[string]DiscountCodeCreation{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	api := obj.api
+	*obj = r["discount_code_creation"]
+	obj.api = api
+
+	return nil
+}
+
+// Wait polls the job every interval until it completes, giving up after
+// timeout.
+func (obj *DiscountCodeCreation) Wait(interval time.Duration, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for !obj.Done() {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Discount code batch %d still %s after %s", obj.ID, obj.Status, timeout)
+		}
+		time.Sleep(interval)
+		if err := obj.Refresh(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DiscountCodes lists the codes the job created, along with those that
+// failed and their Errors.
+func (obj *DiscountCodeCreation) DiscountCodes() ([]*DiscountCode, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/price_rules/%d/batch/%d/discount_codes.json", obj.PriceRuleID, obj.ID)
+	codes, _, err := obj.api.processDiscountCodesResponse(obj.api.requestWithPagination(endpoint, "GET", nil, nil))
+	return codes, err
+}
@@ -0,0 +1,118 @@
+package shopify
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// DEFAULT_DISCOUNT_CODE_ALPHABET leaves out characters that are easy to
+// confuse, like 0 and O or 1 and I.
+const DEFAULT_DISCOUNT_CODE_ALPHABET = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+const DEFAULT_DISCOUNT_CODE_LENGTH = 8
+
+// DiscountCodeGenerator makes random discount codes that don't collide with
+// each other or with codes passed to Exclude. Codes are compared without
+// case, as Shopify does.
+type DiscountCodeGenerator struct {
+	Alphabet string
+	Length   int
+	Prefix   string
+
+	existing map[string]bool
+}
+
+// Exclude marks codes as taken.
+func (g *DiscountCodeGenerator) Exclude(codes ...string) {
+	if g.existing == nil {
+		g.existing = map[string]bool{}
+	}
+	for _, code := range codes {
+		g.existing[strings.ToUpper(code)] = true
+	}
+}
+
+// ExcludeDiscountCodes marks every code in the shop as taken. Codes must be
+// unique across all price rules, so this loads the codes of each one; for
+// shops with many codes, generate candidates and check them with
+// LookupDiscountCode instead.
+func (g *DiscountCodeGenerator) ExcludeDiscountCodes(api *API) error {
+	rules, pages, err := api.PriceRules(nil)
+	for {
+		if err != nil {
+			return err
+		}
+		for _, rule := range rules {
+			if err := g.excludePriceRuleCodes(api, rule.ID); err != nil {
+				return err
+			}
+		}
+		if !pages.HasNextPage() {
+			return nil
+		}
+		rules, pages, err = api.PriceRulesFromPages(pages)
+	}
+}
+
+func (g *DiscountCodeGenerator) excludePriceRuleCodes(api *API, priceRuleID int64) error {
+	codes, pages, err := api.DiscountCodes(priceRuleID)
+	for {
+		if err != nil {
+			return err
+		}
+		for _, code := range codes {
+			g.Exclude(code.Code)
+		}
+		if !pages.HasNextPage() {
+			return nil
+		}
+		codes, pages, err = api.DiscountCodesFromPages(pages)
+	}
+}
+
+// Generate returns n new codes. They are excluded from later calls.
+func (g *DiscountCodeGenerator) Generate(n int) ([]string, error) {
+	alphabet := []rune(g.Alphabet)
+	if len(alphabet) == 0 {
+		alphabet = []rune(DEFAULT_DISCOUNT_CODE_ALPHABET)
+	}
+	length := g.Length
+	if length == 0 {
+		length = DEFAULT_DISCOUNT_CODE_LENGTH
+	}
+
+	// Random codes get slow to find as the space fills up, so refuse to use
+	// more than half of it.
+	space := math.Pow(float64(len(alphabet)), float64(length))
+	if float64(len(g.existing)+n) > space/2 {
+		return nil, fmt.Errorf("Can't generate %d codes of length %d from %d characters", n, length, len(alphabet))
+	}
+
+	if g.existing == nil {
+		g.existing = map[string]bool{}
+	}
+	max := big.NewInt(int64(len(alphabet)))
+	codes := make([]string, 0, n)
+	for len(codes) < n {
+		code := make([]rune, length)
+		for i := range code {
+			index, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return nil, err
+			}
+			code[i] = alphabet[index.Int64()]
+		}
+
+		candidate := g.Prefix + string(code)
+		if g.existing[strings.ToUpper(candidate)] {
+			continue
+		}
+		g.Exclude(candidate)
+		codes = append(codes, candidate)
+	}
+
+	return codes, nil
+}
@@ -0,0 +1,177 @@
+package shopify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiscountCodeGenerator(t *testing.T) {
+	g := &DiscountCodeGenerator{Alphabet: "AB", Length: 4, Prefix: "SALE-"}
+	g.Exclude("sale-aaaa", "SALE-BBBB")
+
+	codes, err := g.Generate(6)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		if !strings.HasPrefix(code, "SALE-") || strings.Trim(code[5:], "AB") != "" || len(code) != 9 {
+			t.Errorf("unexpected code %s", code)
+		}
+		if code == "SALE-AAAA" || code == "SALE-BBBB" || seen[code] {
+			t.Errorf("collision on %s", code)
+		}
+		seen[code] = true
+	}
+
+	if _, err := g.Generate(1); err == nil {
+		t.Errorf("expected an error once half the codes are used")
+	}
+}
+
+func TestCreateDiscountCodesWaits(t *testing.T) {
+	polls := 0
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin/api/2021-07/price_rules/507328175/batch.json":
+			body := map[string][]map[string]string{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if len(body["discount_codes"]) != 2 || body["discount_codes"][0]["code"] != "SUMMER1" {
+				t.Errorf("unexpected body %v", body)
+			}
+			w.WriteHeader(201)
+			w.Write([]byte(`{"discount_code_creation":{"id":173232803,"price_rule_id":507328175,"status":"queued","codes_count":2}}`))
+		case "/admin/api/2021-07/price_rules/507328175/batch/173232803.json":
+			polls++
+			status := "running"
+			if polls > 1 {
+				status = "completed"
+			}
+			w.Write([]byte(`{"discount_code_creation":{"id":173232803,"price_rule_id":507328175,"status":"` + status + `","imported_count":1,"failed_count":1}}`))
+		case "/admin/api/2021-07/price_rules/507328175/batch/173232803/discount_codes.json":
+			w.Write([]byte(`{"discount_codes":[{"id":1,"code":"SUMMER1","errors":{}},{"code":"SUMMER2","errors":{"code":["must be unique"]}}]}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	api := newTestAPI(ts)
+	job, err := api.CreateDiscountCodes(507328175, []string{"SUMMER1", "SUMMER2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := job.Wait(time.Millisecond, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if !job.Done() || polls != 2 || job.FailedCount != 1 {
+		t.Errorf("unexpected job %+v after %d polls", job, polls)
+	}
+
+	codes, err := job.DiscountCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != 2 || len(codes[1].Errors) != 1 {
+		t.Errorf("unexpected codes %+v", codes)
+	}
+
+	if _, err := api.CreateDiscountCodes(507328175, make([]string, MAX_DISCOUNT_CODE_BATCH+1)); err == nil {
+		t.Errorf("expected an error for an oversized batch")
+	}
+}
+
+// memoryRequestCache keys entries by their unexpanded path, since
+// baseRequest looks them up before replacing BASE_PATH but stores them
+// after.
+type memoryRequestCache map[string]*bytes.Buffer
+
+func (c memoryRequestCache) key(key string) string {
+	return strings.Replace(key, "/admin/api/2021-07", "BASE_PATH", 1)
+}
+
+func (c memoryRequestCache) Contains(key string) bool {
+	_, ok := c[c.key(key)]
+	return ok
+}
+
+func (c memoryRequestCache) Get(key string) *bytes.Buffer {
+	return c[c.key(key)]
+}
+
+func (c memoryRequestCache) Set(key string, buf *bytes.Buffer) {
+	c[c.key(key)] = buf
+}
+
+func TestCreateDiscountCodeBatches(t *testing.T) {
+	batches := []int{}
+	polls := map[string]int{}
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			body := map[string][]map[string]string{}
+			json.NewDecoder(r.Body).Decode(&body)
+			batches = append(batches, len(body["discount_codes"]))
+			w.WriteHeader(201)
+			w.Write([]byte(fmt.Sprintf(`{"discount_code_creation":{"id":%d,"price_rule_id":507328175,"status":"queued"}}`, len(batches))))
+			return
+		}
+
+		// jobs complete on their second poll, so a cached first response
+		// would never finish
+		polls[r.URL.Path]++
+		status := "running"
+		if polls[r.URL.Path] > 1 {
+			status = "completed"
+		}
+		w.Write([]byte(fmt.Sprintf(`{"discount_code_creation":{"id":%d,"price_rule_id":507328175,"status":"%s"}}`, len(batches), status)))
+	}))
+	defer ts.Close()
+
+	api := newTestAPI(ts)
+	api.RequestCache = memoryRequestCache{}
+
+	codes := make([]string, MAX_DISCOUNT_CODE_BATCH*2+1)
+	for i := range codes {
+		codes[i] = fmt.Sprintf("CODE%d", i)
+	}
+
+	jobs, err := api.CreateDiscountCodeBatches(507328175, codes, time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 3 || !jobs[2].Done() || fmt.Sprint(batches) != "[100 100 1]" {
+		t.Errorf("unexpected batches %v and jobs %+v", batches, jobs)
+	}
+}
+
+func TestDiscountCodeGeneratorExcludesShopCodes(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin/api/2021-07/price_rules.json":
+			w.Write([]byte(`{"price_rules":[{"id":1},{"id":2}]}`))
+		case "/admin/api/2021-07/price_rules/1/discount_codes.json":
+			w.Write([]byte(`{"discount_codes":[{"id":1,"code":"AAAA"}]}`))
+		case "/admin/api/2021-07/price_rules/2/discount_codes.json":
+			w.Write([]byte(`{"discount_codes":[{"id":2,"code":"bbbb"}]}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	g := &DiscountCodeGenerator{Alphabet: "AB", Length: 4}
+	if err := g.ExcludeDiscountCodes(newTestAPI(ts)); err != nil {
+		t.Fatal(err)
+	}
+	if !g.existing["AAAA"] || !g.existing["BBBB"] {
+		t.Errorf("expected codes from every price rule, got %v", g.existing)
+	}
+}
@@ -0,0 +1,364 @@
+package shopify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const (
+	DraftOrderStatusOpen        = "open"
+	DraftOrderStatusInvoiceSent = "invoice_sent"
+	DraftOrderStatusCompleted   = "completed"
+)
+
+const (
+	AppliedDiscountFixedAmount = "fixed_amount"
+	AppliedDiscountPercentage  = "percentage"
+)
+
+// AppliedDiscount is a discount on a draft order or one of its line items.
+// Value is a percentage or an amount, depending on ValueType.
+type AppliedDiscount struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Value       string `json:"value,omitempty"`
+	ValueType   string `json:"value_type,omitempty"`
+	Amount      string `json:"amount,omitempty"`
+}
+
+// DraftOrderLineItem is either a product variant, with VariantID set, or a
+// custom item with a Title and Price. Taxable and RequiresShipping are left
+// to Shopify when nil.
+type DraftOrderLineItem struct {
+	ID               int64                `json:"id,omitempty"`
+	VariantID        int64                `json:"variant_id,omitempty"`
+	ProductID        int64                `json:"product_id,omitempty"`
+	Title            string               `json:"title,omitempty"`
+	VariantTitle     string               `json:"variant_title,omitempty"`
+	Sku              string               `json:"sku,omitempty"`
+	Vendor           string               `json:"vendor,omitempty"`
+	Price            string               `json:"price,omitempty"`
+	Quantity         int64                `json:"quantity,omitempty"`
+	Grams            int64                `json:"grams,omitempty"`
+	Taxable          *bool                `json:"taxable,omitempty"`
+	RequiresShipping *bool                `json:"requires_shipping,omitempty"`
+	Custom           bool                 `json:"custom,omitempty"`
+	AppliedDiscount  *AppliedDiscount     `json:"applied_discount,omitempty"`
+	Properties       []LineItemProperties `json:"properties,omitempty"`
+}
+
+func DraftOrderVariantLineItem(variantID int64, quantity int64) DraftOrderLineItem {
+	return DraftOrderLineItem{VariantID: variantID, Quantity: quantity}
+}
+
+func DraftOrderCustomLineItem(title string, price string, quantity int64) DraftOrderLineItem {
+	return DraftOrderLineItem{Title: title, Price: price, Quantity: quantity, Custom: true}
+}
+
+type DraftOrderShippingLine struct {
+	Title  string `json:"title,omitempty"`
+	Price  string `json:"price,omitempty"`
+	Handle string `json:"handle,omitempty"`
+	Custom bool   `json:"custom,omitempty"`
+}
+
+type DraftOrder struct {
+	ID                        int64                   `json:"id,omitempty"`
+	OrderID                   int64                   `json:"order_id,omitempty"`
+	Name                      string                  `json:"name,omitempty"`
+	Status                    string                  `json:"status,omitempty"`
+	Email                     string                  `json:"email,omitempty"`
+	Note                      string                  `json:"note,omitempty"`
+	Tags                      string                  `json:"tags,omitempty"`
+	Currency                  string                  `json:"currency,omitempty"`
+	TaxExempt                 bool                    `json:"tax_exempt"`
+	TaxesIncluded             bool                    `json:"taxes_included,omitempty"`
+	LineItems                 []DraftOrderLineItem    `json:"line_items,omitempty"`
+	AppliedDiscount           *AppliedDiscount        `json:"applied_discount,omitempty"`
+	ShippingLine              *DraftOrderShippingLine `json:"shipping_line,omitempty"`
+	BillingAddress            *BillingAddress         `json:"billing_address,omitempty"`
+	ShippingAddress           *BillingAddress         `json:"shipping_address,omitempty"`
+	Customer                  *Customer               `json:"customer,omitempty"`
+	UseCustomerDefaultAddress bool                    `json:"use_customer_default_address,omitempty"`
+	InvoiceURL                string                  `json:"invoice_url,omitempty"`
+	InvoiceSentAt             string                  `json:"invoice_sent_at,omitempty"`
+	SubtotalPrice             string                  `json:"subtotal_price,omitempty"`
+	TotalTax                  string                  `json:"total_tax,omitempty"`
+	TotalPrice                string                  `json:"total_price,omitempty"`
+	CompletedAt               string                  `json:"completed_at,omitempty"`
+	CreatedAt                 string                  `json:"created_at,omitempty"`
+	UpdatedAt                 string                  `json:"updated_at,omitempty"`
+
+	api *API
+}
+
+// DraftOrderInvoice customizes the email sent by SendInvoice. Empty fields
+// use the shop's defaults.
+type DraftOrderInvoice struct {
+	To            string   `json:"to,omitempty"`
+	From          string   `json:"from,omitempty"`
+	Bcc           []string `json:"bcc,omitempty"`
+	Subject       string   `json:"subject,omitempty"`
+	CustomMessage string   `json:"custom_message,omitempty"`
+}
+
+type DraftOrderOptions struct {
+	IDs          string `url:"ids,omitempty"`
+	Limit        int    `url:"limit,omitempty"`
+	SinceID      int64  `url:"since_id,omitempty"`
+	Status       string `url:"status,omitempty"`
+	UpdatedAtMin string `url:"updated_at_min,omitempty"`
+	UpdatedAtMax string `url:"updated_at_max,omitempty"`
+	Fields       string `url:"fields,omitempty"`
+}
+
+type DraftOrdersCountOptions struct {
+	SinceID      int64  `url:"since_id,omitempty"`
+	Status       string `url:"status,omitempty"`
+	UpdatedAtMin string `url:"updated_at_min,omitempty"`
+	UpdatedAtMax string `url:"updated_at_max,omitempty"`
+}
+
+// draftOrderBody sends only the customer's id, since Customer has no
+// omitempty tags and would otherwise blank the customer's details.
+type draftOrderBody struct {
+	*DraftOrder
+	Customer *draftOrderCustomer `json:"customer,omitempty"`
+}
+
+type draftOrderCustomer struct {
+	ID int64 `json:"id"`
+}
+
+func (api *API) DraftOrders(options *DraftOrderOptions) ([]*DraftOrder, *Pages, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("BASE_PATH/draft_orders.json?%v", qs)
+	return api.processDraftOrdersResponse(api.requestWithPagination(endpoint, "GET", nil, nil))
+}
+
+func (api *API) DraftOrdersFromPages(pages *Pages) ([]*DraftOrder, *Pages, error) {
+	if pages.HasNextPage() {
+		return api.processDraftOrdersResponse(api.getNextPage(pages))
+	}
+	return nil, &Pages{}, fmt.Errorf("No next page")
+}
+
+func (api *API) processDraftOrdersResponse(res *bytes.Buffer, status int, pages *Pages, err error) ([]*DraftOrder, *Pages, error) {
+	if err != nil {
+		return nil, pages, err
+	}
+
+	if status != 200 {
+		return nil, pages, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := &map[string][]*DraftOrder{}
+	err = json.NewDecoder(res).Decode(r)
+	if err != nil {
+		return nil, pages, err
+	}
+
+	result := (*r)["draft_orders"]
+	for _, v := range result {
+		v.api = api
+	}
+
+	return result, pages, nil
+}
+
+func (api *API) DraftOrdersCount(options *DraftOrdersCountOptions) (int, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("BASE_PATH/draft_orders/count.json?%v", qs)
+
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if status != 200 {
+		return 0, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := map[string]interface{}{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	result, _ := strconv.Atoi(fmt.Sprintf("%v", r["count"]))
+	if err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+func (api *API) DraftOrder(id int64) (*DraftOrder, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/draft_orders/%d.json", id)
+
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := map[string]DraftOrder{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	result := r["draft_order"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.api = api
+
+	return &result, nil
+}
+
+func (api *API) NewDraftOrder() *DraftOrder {
+	return &DraftOrder{api: api}
+}
+
+func (obj *DraftOrder) Save() error {
+	for i, item := range obj.LineItems {
+		if item.VariantID == 0 && (item.Title == "" || item.Price == "") {
+			return fmt.Errorf("Line item %d needs a variant id, or a title and price", i)
+		}
+	}
+
+	endpoint := fmt.Sprintf("BASE_PATH/draft_orders/%d.json", obj.ID)
+	method := "PUT"
+	expectedStatus := 200
+
+	if obj.ID == 0 {
+		endpoint = "BASE_PATH/draft_orders.json"
+		method = "POST"
+		expectedStatus = 201
+	}
+
+	draft := draftOrderBody{DraftOrder: obj}
+	if obj.Customer != nil {
+		draft.Customer = &draftOrderCustomer{ID: obj.Customer.Id}
+	}
+
+	body := map[string]interface{}{"draft_order": draft}
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	return obj.send(endpoint, method, expectedStatus, buf)
+}
+
+func (obj *DraftOrder) Delete() error {
+	endpoint := fmt.Sprintf("BASE_PATH/draft_orders/%d.json", obj.ID)
+	method := "DELETE"
+	expectedStatus := 200
+
+	res, status, err := obj.api.request(endpoint, method, nil, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return fmt.Errorf("Status %d: %v", status, r.Errors)
+		}
+
+		return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+	}
+
+	return nil
+}
+
+// SendInvoice emails the draft order's invoice to the customer.
+func (obj *DraftOrder) SendInvoice(invoice DraftOrderInvoice) error {
+	endpoint := fmt.Sprintf("BASE_PATH/draft_orders/%d/send_invoice.json", obj.ID)
+	method := "POST"
+
+	body := map[string]DraftOrderInvoice{"draft_order_invoice": invoice}
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	res, status, err := obj.api.request(endpoint, method, nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != 200 && status != 201 {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return fmt.Errorf("Status %d: %v", status, r.Errors)
+		}
+
+		return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+	}
+
+	return nil
+}
+
+// Complete turns the draft into an order and returns the order's id. With
+// paymentPending the order is marked as awaiting payment instead of paid.
+func (obj *DraftOrder) Complete(paymentPending bool) (int64, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/draft_orders/%d/complete.json", obj.ID)
+	if paymentPending {
+		endpoint += "?payment_pending=true"
+	}
+
+	err := obj.send(endpoint, "PUT", 200, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return obj.OrderID, nil
+}
+
+// send makes the request and replaces obj with the returned draft order.
+func (obj *DraftOrder) send(endpoint string, method string, expectedStatus int, buf io.Reader) error {
+	res, status, err := obj.api.request(endpoint, method, nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return fmt.Errorf("Status %d: %v", status, r.Errors)
+		}
+
+		return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+	}
+
+	r := map[string]DraftOrder{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	api := obj.api
+	*obj = r["draft_order"]
+	obj.api = api
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package shopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDraftOrderSaveAndComplete(t *testing.T) {
+	var sent map[string]map[string]interface{}
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin/api/2021-07/draft_orders.json":
+			json.NewDecoder(r.Body).Decode(&sent)
+			w.WriteHeader(201)
+			w.Write([]byte(`{"draft_order":{"id":994118539,"status":"open","tax_exempt":true}}`))
+		case "/admin/api/2021-07/draft_orders/994118539/complete.json":
+			if r.Method != "PUT" || r.URL.Query().Get("payment_pending") != "true" {
+				t.Errorf("unexpected complete request %s %s", r.Method, r.URL)
+			}
+			w.Write([]byte(`{"draft_order":{"id":994118539,"status":"completed","order_id":450789469}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	draft := newTestAPI(ts).NewDraftOrder()
+	draft.TaxExempt = true
+	draft.Customer = &Customer{Id: 207119551, Email: "bob@example.com"}
+	engraving := DraftOrderCustomLineItem("Custom engraving", "15.00", 1)
+	taxable := false
+	engraving.Taxable = &taxable
+	draft.LineItems = []DraftOrderLineItem{
+		DraftOrderVariantLineItem(39072856, 2),
+		engraving,
+	}
+	draft.AppliedDiscount = &AppliedDiscount{Value: "10.0", ValueType: AppliedDiscountPercentage}
+
+	if err := draft.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	customer := sent["draft_order"]["customer"].(map[string]interface{})
+	if len(customer) != 1 || customer["id"] != float64(207119551) {
+		t.Errorf("expected only the customer id, got %v", customer)
+	}
+	if sent["draft_order"]["tax_exempt"] != true {
+		t.Errorf("tax_exempt not sent")
+	}
+	lineItems := sent["draft_order"]["line_items"].([]interface{})
+	if _, ok := lineItems[0].(map[string]interface{})["taxable"]; ok {
+		t.Errorf("expected taxable to be left to Shopify, got %v", lineItems[0])
+	}
+	if taxable, ok := lineItems[1].(map[string]interface{})["taxable"]; !ok || taxable != false {
+		t.Errorf("expected taxable false to be sent, got %v", lineItems[1])
+	}
+	if draft.ID != 994118539 {
+		t.Errorf("draft not updated: %+v", draft)
+	}
+
+	orderID, err := draft.Complete(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if orderID != 450789469 || draft.Status != DraftOrderStatusCompleted {
+		t.Errorf("unexpected completion %d %s", orderID, draft.Status)
+	}
+}
+
+func TestDraftOrderRequiresLineItemDetails(t *testing.T) {
+	draft := (&API{}).NewDraftOrder()
+	draft.LineItems = []DraftOrderLineItem{{Title: "No price", Quantity: 1}}
+	if err := draft.Save(); err == nil {
+		t.Errorf("expected an error for a custom item without a price")
+	}
+}
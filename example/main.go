@@ -2,7 +2,7 @@ package main
 
 import (
 	"fmt"
-	"github.com/boourns/go_shopify"
+	"github.com/anthonymayer/go_shopify"
 	"github.com/gorilla/context"
 	"github.com/gorilla/sessions"
 	"html/template"
@@ -111,12 +111,9 @@ func serveInstall(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// served through app.AppProxyMiddleware, which checks the proxy signature
 func serveAppProxy(w http.ResponseWriter, r *http.Request) {
-	if app.VerifyHMACSignature(r.URL) {
-		http.ServeFile(w, r, "static/app_proxy.html")
-	} else {
-		http.Error(w, "Unauthorized", 401)
-	}
+	http.ServeFile(w, r, "static/app_proxy.html")
 }
 
 // initial page served when visited as embedded app inside Shopify admin
@@ -158,7 +155,7 @@ func serveAdmin(w http.ResponseWriter, r *http.Request) {
 func main() {
 	http.HandleFunc("/install", serveInstall)
 	http.HandleFunc("/admin", serveAdmin)
-	http.HandleFunc("/app_proxy/", serveAppProxy)
+	http.Handle("/app_proxy/", app.AppProxyMiddleware(http.HandlerFunc(serveAppProxy)))
 
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		http.ServeFile(w, r, "static/home.html")
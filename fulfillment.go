@@ -0,0 +1,120 @@
+package shopify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+type TrackingInfo struct {
+	Number  string `json:"number,omitempty"`
+	URL     string `json:"url,omitempty"`
+	Company string `json:"company,omitempty"`
+}
+
+// FulfillmentOrderLineItemQuantity picks how many of a fulfillment order
+// line item to fulfill.
+type FulfillmentOrderLineItemQuantity struct {
+	ID       int64 `json:"id"`
+	Quantity int64 `json:"quantity"`
+}
+
+// FulfillmentOrderLineItems selects items from one fulfillment order. Leave
+// FulfillmentOrderLineItems empty to fulfill everything remaining on it.
+type FulfillmentOrderLineItems struct {
+	FulfillmentOrderID        int64                              `json:"fulfillment_order_id"`
+	FulfillmentOrderLineItems []FulfillmentOrderLineItemQuantity `json:"fulfillment_order_line_items,omitempty"`
+}
+
+type Fulfillment struct {
+	CreatedAt       string     `json:"created_at,omitempty"`
+	ID              int64      `json:"id,omitempty"`
+	LineItems       []LineItem `json:"line_items,omitempty"`
+	LocationID      int64      `json:"location_id,omitempty"`
+	Name            string     `json:"name,omitempty"`
+	OrderID         int64      `json:"order_id,omitempty"`
+	Service         string     `json:"service,omitempty"`
+	ShipmentStatus  string     `json:"shipment_status,omitempty"`
+	Status          string     `json:"status,omitempty"`
+	TrackingCompany string     `json:"tracking_company,omitempty"`
+	TrackingNumber  string     `json:"tracking_number,omitempty"`
+	TrackingNumbers []string   `json:"tracking_numbers,omitempty"`
+	TrackingURL     string     `json:"tracking_url,omitempty"`
+	TrackingURLs    []string   `json:"tracking_urls,omitempty"`
+	UpdatedAt       string     `json:"updated_at,omitempty"`
+
+	api *API
+}
+
+// FulfillmentRequest creates a fulfillment against fulfillment order line
+// items.
+type FulfillmentRequest struct {
+	Message                     string                      `json:"message,omitempty"`
+	NotifyCustomer              bool                        `json:"notify_customer"`
+	TrackingInfo                *TrackingInfo               `json:"tracking_info,omitempty"`
+	LineItemsByFulfillmentOrder []FulfillmentOrderLineItems `json:"line_items_by_fulfillment_order"`
+}
+
+func (api *API) CreateFulfillment(request *FulfillmentRequest) (*Fulfillment, error) {
+	result := &Fulfillment{api: api}
+	err := result.post("BASE_PATH/fulfillments.json", 201, map[string]interface{}{"fulfillment": request})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// UpdateTracking replaces the fulfillment's tracking details.
+func (obj *Fulfillment) UpdateTracking(tracking TrackingInfo, notifyCustomer bool) error {
+	endpoint := fmt.Sprintf("BASE_PATH/fulfillments/%d/update_tracking.json", obj.ID)
+	return obj.post(endpoint, 200, map[string]interface{}{
+		"fulfillment": map[string]interface{}{
+			"notify_customer": notifyCustomer,
+			"tracking_info":   tracking,
+		},
+	})
+}
+
+func (obj *Fulfillment) Cancel() error {
+	endpoint := fmt.Sprintf("BASE_PATH/fulfillments/%d/cancel.json", obj.ID)
+	return obj.post(endpoint, 200, map[string]interface{}{})
+}
+
+// post sends body to endpoint and replaces obj with the returned fulfillment.
+func (obj *Fulfillment) post(endpoint string, expectedStatus int, body interface{}) error {
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	res, status, err := obj.api.request(endpoint, "POST", nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return fmt.Errorf("Status %d: %v", status, r.Errors)
+		}
+
+		return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+	}
+
+	r := map[string]Fulfillment{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	api := obj.api
+	*obj = r["fulfillment"]
+	obj.api = api
+
+	return nil
+}
@@ -0,0 +1,256 @@
+package shopify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const (
+	FulfillmentOrderStatusOpen       = "open"
+	FulfillmentOrderStatusInProgress = "in_progress"
+	FulfillmentOrderStatusScheduled  = "scheduled"
+	FulfillmentOrderStatusOnHold     = "on_hold"
+	FulfillmentOrderStatusCancelled  = "cancelled"
+	FulfillmentOrderStatusClosed     = "closed"
+	FulfillmentOrderStatusIncomplete = "incomplete"
+)
+
+type FulfillmentOrderDestination struct {
+	ID        int64  `json:"id,omitempty"`
+	Address1  string `json:"address1,omitempty"`
+	Address2  string `json:"address2,omitempty"`
+	City      string `json:"city,omitempty"`
+	Company   string `json:"company,omitempty"`
+	Country   string `json:"country,omitempty"`
+	Email     string `json:"email,omitempty"`
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+	Phone     string `json:"phone,omitempty"`
+	Province  string `json:"province,omitempty"`
+	Zip       string `json:"zip,omitempty"`
+}
+
+type FulfillmentOrderAssignedLocation struct {
+	LocationID  int64  `json:"location_id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Address1    string `json:"address1,omitempty"`
+	Address2    string `json:"address2,omitempty"`
+	City        string `json:"city,omitempty"`
+	CountryCode string `json:"country_code,omitempty"`
+	Phone       string `json:"phone,omitempty"`
+	Province    string `json:"province,omitempty"`
+	Zip         string `json:"zip,omitempty"`
+}
+
+type FulfillmentOrderLineItem struct {
+	ID                  int64 `json:"id,omitempty"`
+	ShopID              int64 `json:"shop_id,omitempty"`
+	FulfillmentOrderID  int64 `json:"fulfillment_order_id,omitempty"`
+	LineItemID          int64 `json:"line_item_id,omitempty"`
+	InventoryItemID     int64 `json:"inventory_item_id,omitempty"`
+	VariantID           int64 `json:"variant_id,omitempty"`
+	Quantity            int64 `json:"quantity,omitempty"`
+	FulfillableQuantity int64 `json:"fulfillable_quantity,omitempty"`
+}
+
+type FulfillmentHold struct {
+	Reason         string `json:"reason,omitempty"`
+	ReasonNotes    string `json:"reason_notes,omitempty"`
+	NotifyMerchant bool   `json:"notify_merchant,omitempty"`
+}
+
+type FulfillmentOrder struct {
+	ID                 int64                            `json:"id,omitempty"`
+	ShopID             int64                            `json:"shop_id,omitempty"`
+	OrderID            int64                            `json:"order_id,omitempty"`
+	AssignedLocationID int64                            `json:"assigned_location_id,omitempty"`
+	AssignedLocation   FulfillmentOrderAssignedLocation `json:"assigned_location,omitempty"`
+	Destination        FulfillmentOrderDestination      `json:"destination,omitempty"`
+	FulfillAt          string                           `json:"fulfill_at,omitempty"`
+	FulfillmentHolds   []FulfillmentHold                `json:"fulfillment_holds,omitempty"`
+	LineItems          []FulfillmentOrderLineItem       `json:"line_items,omitempty"`
+	RequestStatus      string                           `json:"request_status,omitempty"`
+	Status             string                           `json:"status,omitempty"`
+	SupportedActions   []string                         `json:"supported_actions,omitempty"`
+	CreatedAt          string                           `json:"created_at,omitempty"`
+	UpdatedAt          string                           `json:"updated_at,omitempty"`
+
+	api *API
+}
+
+func (api *API) OrderFulfillmentOrders(orderID int64) ([]*FulfillmentOrder, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/orders/%d/fulfillment_orders.json", orderID)
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := &map[string][]*FulfillmentOrder{}
+	err = json.NewDecoder(res).Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := (*r)["fulfillment_orders"]
+	for _, v := range result {
+		v.api = api
+	}
+
+	return result, nil
+}
+
+func (obj *Order) FulfillmentOrders() ([]*FulfillmentOrder, error) {
+	return obj.api.OrderFulfillmentOrders(obj.Id)
+}
+
+func (api *API) FulfillmentOrder(id int64) (*FulfillmentOrder, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/fulfillment_orders/%d.json", id)
+
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := map[string]FulfillmentOrder{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	result := r["fulfillment_order"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.api = api
+
+	return &result, nil
+}
+
+// Move sends the fulfillment order's remaining items to another location.
+// It returns the fulfillment order that now holds the moved items; obj is
+// updated to its original state after the move.
+func (obj *FulfillmentOrder) Move(newLocationID int64) (*FulfillmentOrder, error) {
+	body := map[string]interface{}{
+		"fulfillment_order": map[string]interface{}{"new_location_id": newLocationID},
+	}
+	r, err := obj.post("move", body)
+	if err != nil {
+		return nil, err
+	}
+
+	obj.replace(r["original_fulfillment_order"])
+	moved := r["moved_fulfillment_order"]
+	if moved == nil {
+		return nil, nil
+	}
+	moved.api = obj.api
+	return moved, nil
+}
+
+// Hold stops the fulfillment order from being fulfilled until ReleaseHold.
+func (obj *FulfillmentOrder) Hold(hold FulfillmentHold) error {
+	r, err := obj.post("hold", map[string]interface{}{"fulfillment_hold": hold})
+	if err != nil {
+		return err
+	}
+	obj.replace(r["fulfillment_order"])
+	return nil
+}
+
+func (obj *FulfillmentOrder) ReleaseHold() error {
+	r, err := obj.post("release_hold", nil)
+	if err != nil {
+		return err
+	}
+	obj.replace(r["fulfillment_order"])
+	return nil
+}
+
+// Cancel the fulfillment order. Shopify may return a replacement holding
+// the unfulfilled items; it is nil otherwise.
+func (obj *FulfillmentOrder) Cancel() (*FulfillmentOrder, error) {
+	r, err := obj.post("cancel", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	obj.replace(r["fulfillment_order"])
+	replacement := r["replacement_fulfillment_order"]
+	if replacement != nil {
+		replacement.api = obj.api
+	}
+	return replacement, nil
+}
+
+// Close marks the fulfillment order as incomplete, with an optional message.
+func (obj *FulfillmentOrder) Close(message string) error {
+	body := map[string]interface{}{
+		"fulfillment_order": map[string]interface{}{"message": message},
+	}
+	r, err := obj.post("close", body)
+	if err != nil {
+		return err
+	}
+	obj.replace(r["fulfillment_order"])
+	return nil
+}
+
+func (obj *FulfillmentOrder) replace(updated *FulfillmentOrder) {
+	if updated == nil {
+		return
+	}
+	api := obj.api
+	*obj = *updated
+	obj.api = api
+}
+
+func (obj *FulfillmentOrder) post(action string, body interface{}) (map[string]*FulfillmentOrder, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/fulfillment_orders/%d/%s.json", obj.ID, action)
+	method := "POST"
+	expectedStatus := 200
+
+	var buf io.Reader
+	if body != nil {
+		b := &bytes.Buffer{}
+		if err := json.NewEncoder(b).Encode(body); err != nil {
+			return nil, err
+		}
+		buf = b
+	}
+
+	res, status, err := obj.api.request(endpoint, method, nil, buf)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return nil, fmt.Errorf("Status %d: %v", status, r.Errors)
+		}
+
+		return nil, fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+	}
+
+	r := map[string]*FulfillmentOrder{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
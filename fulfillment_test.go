@@ -0,0 +1,58 @@
+package shopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateFulfillment(t *testing.T) {
+	var sent map[string]FulfillmentRequest
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/admin/api/2021-07/fulfillments.json" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.WriteHeader(201)
+		w.Write([]byte(`{"fulfillment":{"id":1022782888,"order_id":450789469,"status":"success","tracking_number":"1Z001985YW99744790"}}`))
+	}))
+	defer ts.Close()
+
+	fulfillment, err := newTestAPI(ts).CreateFulfillment(&FulfillmentRequest{
+		NotifyCustomer: true,
+		TrackingInfo:   &TrackingInfo{Number: "1Z001985YW99744790", Company: "UPS"},
+		LineItemsByFulfillmentOrder: []FulfillmentOrderLineItems{{
+			FulfillmentOrderID:        1046000786,
+			FulfillmentOrderLineItems: []FulfillmentOrderLineItemQuantity{{ID: 1025578639, Quantity: 1}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	request := sent["fulfillment"]
+	if request.TrackingInfo == nil || request.TrackingInfo.Company != "UPS" ||
+		request.LineItemsByFulfillmentOrder[0].FulfillmentOrderLineItems[0].ID != 1025578639 {
+		t.Errorf("unexpected request body: %#v", request)
+	}
+	if fulfillment.ID != 1022782888 || fulfillment.api == nil {
+		t.Errorf("unexpected fulfillment: %#v", fulfillment)
+	}
+}
+
+func TestOrderDecodesFulfillments(t *testing.T) {
+	payload, err := NewWebhookRegistry().Decode(&API{}, "orders/updated",
+		[]byte(`{"id":450789469,"fulfillments":[{"id":255858046,"tracking_urls":["https://ups.com/1Z"],"line_items":[{"id":466157049,"quantity":1}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	order := payload.(*Order)
+	if len(order.Fulfillments) != 1 || order.Fulfillments[0].LineItems[0].Id != 466157049 {
+		t.Fatalf("fulfillments not decoded: %#v", order.Fulfillments)
+	}
+	if order.Fulfillments[0].api == nil {
+		t.Errorf("fulfillment not bound to the API")
+	}
+}
@@ -0,0 +1,311 @@
+package shopify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	GiftCardStatusEnabled  = "enabled"
+	GiftCardStatusDisabled = "disabled"
+)
+
+// GiftCardCode holds a full gift card code. It prints and marshals to JSON
+// masked, so cards can be logged or stored with %v, %+v or json.Marshal; use
+// Reveal to get the code itself.
+type GiftCardCode string
+
+func (c GiftCardCode) Reveal() string {
+	return string(c)
+}
+
+func (c GiftCardCode) String() string {
+	return MaskGiftCardCode(string(c))
+}
+
+func (c GiftCardCode) GoString() string {
+	return strconv.Quote(c.String())
+}
+
+func (c GiftCardCode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.String())
+}
+
+// MaskGiftCardCode hides all but the last four characters of code.
+func MaskGiftCardCode(code string) string {
+	if code == "" {
+		return ""
+	}
+	code = strings.ReplaceAll(code, " ", "")
+	if len(code) <= 4 {
+		return strings.Repeat("*", len(code))
+	}
+	return strings.Repeat("*", len(code)-4) + code[len(code)-4:]
+}
+
+// GiftCard is only available to Shopify Plus shops. Code is set when
+// creating a card, and is only returned in the creation response; later
+// reads only have LastCharacters.
+type GiftCard struct {
+	ID             int64        `json:"id,omitempty"`
+	Code           GiftCardCode `json:"code,omitempty"`
+	LastCharacters string       `json:"last_characters,omitempty"`
+	InitialValue   string       `json:"initial_value,omitempty"`
+	Balance        string       `json:"balance,omitempty"`
+	Currency       string       `json:"currency,omitempty"`
+	Note           string       `json:"note,omitempty"`
+	ExpiresOn      string       `json:"expires_on,omitempty"`
+	TemplateSuffix string       `json:"template_suffix,omitempty"`
+	CustomerID     int64        `json:"customer_id,omitempty"`
+	OrderID        int64        `json:"order_id,omitempty"`
+	LineItemID     int64        `json:"line_item_id,omitempty"`
+	UserID         int64        `json:"user_id,omitempty"`
+	APIClientID    int64        `json:"api_client_id,omitempty"`
+	DisabledAt     string       `json:"disabled_at,omitempty"`
+	CreatedAt      string       `json:"created_at,omitempty"`
+	UpdatedAt      string       `json:"updated_at,omitempty"`
+
+	api *API
+}
+
+type GiftCardOptions struct {
+	Status  string `url:"status,omitempty"`
+	Limit   int    `url:"limit,omitempty"`
+	SinceID int64  `url:"since_id,omitempty"`
+	Fields  string `url:"fields,omitempty"`
+}
+
+type GiftCardsCountOptions struct {
+	Status string `url:"status,omitempty"`
+}
+
+// GiftCardSearchOptions searches by balance, initial_value, email,
+// last_characters, and the created, updated and disabled dates; for
+// example "balance:>0 last_characters:1234".
+type GiftCardSearchOptions struct {
+	Query  string `url:"query,omitempty"`
+	Order  string `url:"order,omitempty"`
+	Limit  int    `url:"limit,omitempty"`
+	Fields string `url:"fields,omitempty"`
+}
+
+func (api *API) GiftCards(options *GiftCardOptions) ([]*GiftCard, *Pages, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("BASE_PATH/gift_cards.json?%v", qs)
+	return api.processGiftCardsResponse(api.requestWithPagination(endpoint, "GET", nil, nil))
+}
+
+func (api *API) SearchGiftCards(options *GiftCardSearchOptions) ([]*GiftCard, *Pages, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("BASE_PATH/gift_cards/search.json?%v", qs)
+	return api.processGiftCardsResponse(api.requestWithPagination(endpoint, "GET", nil, nil))
+}
+
+func (api *API) GiftCardsFromPages(pages *Pages) ([]*GiftCard, *Pages, error) {
+	if pages.HasNextPage() {
+		return api.processGiftCardsResponse(api.getNextPage(pages))
+	}
+	return nil, &Pages{}, fmt.Errorf("No next page")
+}
+
+func (api *API) processGiftCardsResponse(res *bytes.Buffer, status int, pages *Pages, err error) ([]*GiftCard, *Pages, error) {
+	if err != nil {
+		return nil, pages, err
+	}
+
+	if status != 200 {
+		return nil, pages, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := &map[string][]*GiftCard{}
+	err = json.NewDecoder(res).Decode(r)
+	if err != nil {
+		return nil, pages, err
+	}
+
+	result := (*r)["gift_cards"]
+	for _, v := range result {
+		v.api = api
+	}
+
+	return result, pages, nil
+}
+
+// CustomerGiftCards returns every gift card issued to a customer. The API
+// can't filter gift cards by customer, so this pages through all of them.
+func (api *API) CustomerGiftCards(customerID int64) ([]*GiftCard, error) {
+	result := []*GiftCard{}
+
+	cards, pages, err := api.GiftCards(&GiftCardOptions{Limit: 250})
+	for {
+		if err != nil {
+			return nil, err
+		}
+		for _, card := range cards {
+			if card.CustomerID == customerID {
+				result = append(result, card)
+			}
+		}
+		if !pages.HasNextPage() {
+			return result, nil
+		}
+		cards, pages, err = api.GiftCardsFromPages(pages)
+	}
+}
+
+func (api *API) GiftCardsCount(options *GiftCardsCountOptions) (int, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("BASE_PATH/gift_cards/count.json?%v", qs)
+
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if status != 200 {
+		return 0, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := map[string]interface{}{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	result, _ := strconv.Atoi(fmt.Sprintf("%v", r["count"]))
+	if err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+func (api *API) GiftCard(id int64) (*GiftCard, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/gift_cards/%d.json", id)
+
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := map[string]GiftCard{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	result := r["gift_card"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.api = api
+
+	return &result, nil
+}
+
+// NewGiftCard starts a gift card worth initialValue. Leave Code empty to
+// have Shopify generate one.
+func (api *API) NewGiftCard(initialValue string, currency string) *GiftCard {
+	return &GiftCard{InitialValue: initialValue, Currency: currency, api: api}
+}
+
+// Save creates the gift card, or updates the fields Shopify allows to
+// change: note, expiry, template suffix and customer.
+func (obj *GiftCard) Save() error {
+	endpoint := fmt.Sprintf("BASE_PATH/gift_cards/%d.json", obj.ID)
+	method := "PUT"
+	expectedStatus := 200
+
+	update := map[string]interface{}{
+		"id":              obj.ID,
+		"note":            obj.Note,
+		"expires_on":      obj.ExpiresOn,
+		"template_suffix": obj.TemplateSuffix,
+	}
+	if obj.CustomerID != 0 {
+		update["customer_id"] = obj.CustomerID
+	}
+	var card interface{} = update
+
+	if obj.ID == 0 {
+		endpoint = "BASE_PATH/gift_cards.json"
+		method = "POST"
+		expectedStatus = 201
+		card = giftCardCreate{GiftCard: obj, Code: obj.Code.Reveal()}
+	}
+
+	body := map[string]interface{}{"gift_card": card}
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	return obj.send(endpoint, method, expectedStatus, buf)
+}
+
+// giftCardCreate is the creation body, the only place the full code is
+// sent.
+type giftCardCreate struct {
+	*GiftCard
+	Code string `json:"code,omitempty"`
+}
+
+// Disable permanently; disabled gift cards can't be enabled again.
+func (obj *GiftCard) Disable() error {
+	endpoint := fmt.Sprintf("BASE_PATH/gift_cards/%d/disable.json", obj.ID)
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(map[string]interface{}{
+		"gift_card": map[string]int64{"id": obj.ID},
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return obj.send(endpoint, "POST", 200, buf)
+}
+
+// send makes the request and replaces obj with the returned gift card,
+// keeping the code if the response leaves it out.
+func (obj *GiftCard) send(endpoint string, method string, expectedStatus int, buf *bytes.Buffer) error {
+	res, status, err := obj.api.request(endpoint, method, nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return fmt.Errorf("Status %d: %v", status, r.Errors)
+		}
+
+		return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+	}
+
+	r := map[string]GiftCard{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	api := obj.api
+	code := obj.Code
+	*obj = r["gift_card"]
+	obj.api = api
+	if obj.Code == "" {
+		obj.Code = code
+	}
+
+	return nil
+}
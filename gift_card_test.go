@@ -0,0 +1,75 @@
+package shopify
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGiftCardCodeIsMasked(t *testing.T) {
+	card := &GiftCard{ID: 1, Code: "7f3a 9bc2 e1d4 4d5c"}
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+		out := fmt.Sprintf(format, card)
+		if strings.Contains(out, "7f3a") {
+			t.Errorf("%s leaked the code: %s", format, out)
+		}
+	}
+
+	if card.Code.String() != "************4d5c" {
+		t.Errorf("unexpected mask %s", card.Code)
+	}
+	if card.Code.Reveal() != "7f3a 9bc2 e1d4 4d5c" {
+		t.Errorf("Reveal lost the code")
+	}
+
+	out, err := json.Marshal(&GiftCard{ID: 1, Code: "7f3a 9bc2 e1d4 4d5c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `"code":"************4d5c"`) {
+		t.Errorf("json.Marshal leaked the code: %s", out)
+	}
+}
+
+func TestGiftCardSaveSendsUpdatableFields(t *testing.T) {
+	var sent map[string]map[string]interface{}
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		switch r.Method {
+		case "POST":
+			w.WriteHeader(201)
+			w.Write([]byte(`{"gift_card":{"id":48394658,"code":"ABCD1234EFGH5678","last_characters":"5678","initial_value":"25.00","balance":"25.00"}}`))
+		case "PUT":
+			w.Write([]byte(`{"gift_card":{"id":48394658,"last_characters":"5678","note":"Thanks","balance":"25.00"}}`))
+		}
+	}))
+	defer ts.Close()
+
+	card := newTestAPI(ts).NewGiftCard("25.00", "USD")
+	card.Code = "ABCD1234EFGH5678"
+	card.ExpiresOn = "2030-01-01"
+	if err := card.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if sent["gift_card"]["initial_value"] != "25.00" || sent["gift_card"]["currency"] != "USD" || sent["gift_card"]["code"] != "ABCD1234EFGH5678" {
+		t.Errorf("unexpected create body %v", sent)
+	}
+
+	card.Note = "Thanks"
+	if err := card.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := sent["gift_card"]["code"]; ok {
+		t.Errorf("update sent the code: %v", sent)
+	}
+	if _, ok := sent["gift_card"]["balance"]; ok {
+		t.Errorf("update sent the balance: %v", sent)
+	}
+	if card.Code.Reveal() != "ABCD1234EFGH5678" || card.Note != "Thanks" {
+		t.Errorf("unexpected card %+v", card)
+	}
+}
@@ -3,7 +3,6 @@ module github.com/anthonymayer/go_shopify
 go 1.14
 
 require (
-	github.com/boourns/go_shopify v0.0.0-20141213174713-1102c9e5ebb8
 	github.com/google/go-querystring v1.0.0
 	github.com/gorilla/context v1.1.1
 	github.com/gorilla/sessions v1.2.0
@@ -0,0 +1,150 @@
+package shopify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type CountryHarmonizedSystemCode struct {
+	CountryCode          string `json:"country_code,omitempty"`
+	HarmonizedSystemCode string `json:"harmonized_system_code,omitempty"`
+}
+
+type InventoryItem struct {
+	CountryCodeOfOrigin          string                        `json:"country_code_of_origin,omitempty"`
+	CountryHarmonizedSystemCodes []CountryHarmonizedSystemCode `json:"country_harmonized_system_codes,omitempty"`
+	Cost                         string                        `json:"cost,omitempty"`
+	CreatedAt                    string                        `json:"created_at,omitempty"`
+	HarmonizedSystemCode         string                        `json:"harmonized_system_code,omitempty"`
+	ID                           int64                         `json:"id,omitempty"`
+	ProvinceCodeOfOrigin         string                        `json:"province_code_of_origin,omitempty"`
+	RequiresShipping             bool                          `json:"requires_shipping,omitempty"`
+	Sku                          string                        `json:"sku,omitempty"`
+	Tracked                      bool                          `json:"tracked"`
+	UpdatedAt                    string                        `json:"updated_at,omitempty"`
+
+	api *API
+}
+
+type InventoryItemOptions struct {
+	IDs   string `url:"ids,omitempty"`
+	Limit int    `url:"limit,omitempty"`
+}
+
+// InventoryItems Retrieve inventory items by id; options.IDs is required
+func (api *API) InventoryItems(options *InventoryItemOptions) ([]*InventoryItem, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("BASE_PATH/inventory_items.json?%v", qs)
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := &map[string][]*InventoryItem{}
+	err = json.NewDecoder(res).Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := (*r)["inventory_items"]
+	for _, v := range result {
+		v.api = api
+	}
+
+	return result, nil
+}
+
+func (api *API) InventoryItem(id int64) (*InventoryItem, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/inventory_items/%d.json", id)
+
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := map[string]InventoryItem{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	result := r["inventory_item"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.api = api
+
+	return &result, nil
+}
+
+// Save updates the inventory item. Shopify creates inventory items along
+// with their variants, so there is no create.
+func (obj *InventoryItem) Save() error {
+	if obj.ID == 0 {
+		return errors.New("InventoryItem has no ID")
+	}
+
+	endpoint := fmt.Sprintf("BASE_PATH/inventory_items/%d.json", obj.ID)
+	method := "PUT"
+	expectedStatus := 200
+
+	body := map[string]*InventoryItem{}
+	body["inventory_item"] = obj
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	res, status, err := obj.api.request(endpoint, method, nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return fmt.Errorf("Status %d: %v", status, r.Errors)
+		}
+
+		return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+	}
+
+	r := map[string]InventoryItem{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	api := obj.api
+	*obj = r["inventory_item"]
+	obj.api = api
+
+	return nil
+}
+
+func (obj *InventoryItem) InventoryLevels() ([]*InventoryLevel, error) {
+	if obj == nil {
+		return nil, errors.New("InventoryItem is nil")
+	}
+	if obj.api == nil {
+		return nil, errors.New("InventoryItem has no API")
+	}
+	return obj.api.InventoryLevels(&InventoryLevelOptions{InventoryItemIDs: fmt.Sprintf("%d", obj.ID)})
+}
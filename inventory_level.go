@@ -0,0 +1,197 @@
+package shopify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type InventoryLevel struct {
+	Available       int64  `json:"available"`
+	InventoryItemID int64  `json:"inventory_item_id,omitempty"`
+	LocationID      int64  `json:"location_id,omitempty"`
+	UpdatedAt       string `json:"updated_at,omitempty"`
+
+	api *API
+}
+
+// InventoryLevelOptions needs InventoryItemIDs or LocationIDs, as comma
+// separated ids.
+type InventoryLevelOptions struct {
+	InventoryItemIDs string `url:"inventory_item_ids,omitempty"`
+	LocationIDs      string `url:"location_ids,omitempty"`
+	Limit            int    `url:"limit,omitempty"`
+	UpdatedAtMin     string `url:"updated_at_min,omitempty"`
+}
+
+func (api *API) InventoryLevels(options *InventoryLevelOptions) ([]*InventoryLevel, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("BASE_PATH/inventory_levels.json?%v", qs)
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := &map[string][]*InventoryLevel{}
+	err = json.NewDecoder(res).Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := (*r)["inventory_levels"]
+	for _, v := range result {
+		v.api = api
+	}
+
+	return result, nil
+}
+
+func (obj *Location) InventoryLevels() ([]*InventoryLevel, error) {
+	if obj == nil {
+		return nil, errors.New("Location is nil")
+	}
+	if obj.api == nil {
+		return nil, errors.New("Location has no API")
+	}
+	return obj.api.InventoryLevels(&InventoryLevelOptions{LocationIDs: fmt.Sprintf("%d", obj.Id)})
+}
+
+// VariantInventoryLevels returns the variant's inventory level at each of
+// the shop's locations where it is stocked.
+func (api *API) VariantInventoryLevels(variant *Variant) ([]*InventoryLevel, error) {
+	if variant.InventoryItemID == 0 {
+		return nil, fmt.Errorf("Variant %d has no inventory item", variant.ID)
+	}
+
+	locations, err := api.Locations()
+	if err != nil {
+		return nil, err
+	}
+	if len(locations) == 0 {
+		return []*InventoryLevel{}, nil
+	}
+
+	ids := []string{}
+	for _, location := range locations {
+		ids = append(ids, fmt.Sprintf("%d", location.Id))
+	}
+
+	return api.InventoryLevels(&InventoryLevelOptions{
+		InventoryItemIDs: fmt.Sprintf("%d", variant.InventoryItemID),
+		LocationIDs:      strings.Join(ids, ","),
+	})
+}
+
+func (obj *Variant) InventoryLevels() ([]*InventoryLevel, error) {
+	if obj == nil {
+		return nil, errors.New("Variant is nil")
+	}
+	if obj.api == nil {
+		return nil, errors.New("Variant has no API")
+	}
+	return obj.api.VariantInventoryLevels(obj)
+}
+
+// AdjustInventoryLevel changes the available quantity at a location by
+// adjustment, which may be negative.
+func (api *API) AdjustInventoryLevel(inventoryItemID int64, locationID int64, adjustment int64) (*InventoryLevel, error) {
+	return api.postInventoryLevel("adjust", 200, map[string]interface{}{
+		"inventory_item_id":    inventoryItemID,
+		"location_id":          locationID,
+		"available_adjustment": adjustment,
+	})
+}
+
+// SetInventoryLevel sets the available quantity at a location. With
+// disconnectIfNecessary, the item is disconnected from other locations if
+// it can only be stocked at one.
+func (api *API) SetInventoryLevel(inventoryItemID int64, locationID int64, available int64, disconnectIfNecessary bool) (*InventoryLevel, error) {
+	return api.postInventoryLevel("set", 200, map[string]interface{}{
+		"inventory_item_id":       inventoryItemID,
+		"location_id":             locationID,
+		"available":               available,
+		"disconnect_if_necessary": disconnectIfNecessary,
+	})
+}
+
+// ConnectInventoryLevel stocks an item at a location. With
+// relocateIfNecessary, the item is moved from another location if it can
+// only be stocked at one.
+func (api *API) ConnectInventoryLevel(inventoryItemID int64, locationID int64, relocateIfNecessary bool) (*InventoryLevel, error) {
+	return api.postInventoryLevel("connect", 201, map[string]interface{}{
+		"inventory_item_id":     inventoryItemID,
+		"location_id":           locationID,
+		"relocate_if_necessary": relocateIfNecessary,
+	})
+}
+
+// DeleteInventoryLevel stops stocking an item at a location.
+func (api *API) DeleteInventoryLevel(inventoryItemID int64, locationID int64) error {
+	endpoint := fmt.Sprintf("BASE_PATH/inventory_levels.json?inventory_item_id=%d&location_id=%d", inventoryItemID, locationID)
+	method := "DELETE"
+	expectedStatus := 204
+
+	res, status, err := api.request(endpoint, method, nil, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return fmt.Errorf("Status %d: %v", status, r.Errors)
+		}
+
+		return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+	}
+
+	return nil
+}
+
+func (api *API) postInventoryLevel(action string, expectedStatus int, body map[string]interface{}) (*InventoryLevel, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/inventory_levels/%s.json", action)
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, status, err := api.request(endpoint, "POST", nil, buf)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return nil, fmt.Errorf("Status %d: %v", status, r.Errors)
+		}
+
+		return nil, fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+	}
+
+	r := map[string]InventoryLevel{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := r["inventory_level"]
+	result.api = api
+
+	return &result, nil
+}
@@ -0,0 +1,131 @@
+package shopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInventoryLevelActions(t *testing.T) {
+	sent := map[string]map[string]interface{}{}
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			q := r.URL.Query()
+			if r.URL.Path != "/admin/api/2021-07/inventory_levels.json" || q.Get("inventory_item_id") != "808950810" || q.Get("location_id") != "905684977" {
+				t.Errorf("unexpected delete %s", r.URL)
+			}
+			w.WriteHeader(204)
+			return
+		}
+
+		body := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		sent[r.URL.Path] = body
+
+		switch r.URL.Path {
+		case "/admin/api/2021-07/inventory_levels/adjust.json":
+			w.Write([]byte(`{"inventory_level":{"inventory_item_id":808950810,"location_id":905684977,"available":6}}`))
+		case "/admin/api/2021-07/inventory_levels/set.json":
+			w.Write([]byte(`{"inventory_level":{"inventory_item_id":808950810,"location_id":905684977,"available":42}}`))
+		case "/admin/api/2021-07/inventory_levels/connect.json":
+			w.WriteHeader(201)
+			w.Write([]byte(`{"inventory_level":{"inventory_item_id":808950810,"location_id":905684977,"available":0}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	api := newTestAPI(ts)
+
+	level, err := api.AdjustInventoryLevel(808950810, 905684977, -2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if level.Available != 6 || level.api == nil {
+		t.Errorf("unexpected level %+v", level)
+	}
+	if body := sent["/admin/api/2021-07/inventory_levels/adjust.json"]; body["available_adjustment"] != float64(-2) || body["inventory_item_id"] != float64(808950810) {
+		t.Errorf("unexpected adjust body %v", body)
+	}
+
+	level, err = api.SetInventoryLevel(808950810, 905684977, 42, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if level.Available != 42 {
+		t.Errorf("unexpected level %+v", level)
+	}
+	if body := sent["/admin/api/2021-07/inventory_levels/set.json"]; body["available"] != float64(42) || body["disconnect_if_necessary"] != true {
+		t.Errorf("unexpected set body %v", body)
+	}
+
+	level, err = api.ConnectInventoryLevel(808950810, 905684977, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if level.LocationID != 905684977 {
+		t.Errorf("unexpected level %+v", level)
+	}
+	if body := sent["/admin/api/2021-07/inventory_levels/connect.json"]; body["relocate_if_necessary"] != false || body["location_id"] != float64(905684977) {
+		t.Errorf("unexpected connect body %v", body)
+	}
+
+	if err := api.DeleteInventoryLevel(808950810, 905684977); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInventoryLevelErrors(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(422)
+		w.Write([]byte(`{"errors":["Inventory item does not have inventory tracking enabled"]}`))
+	}))
+	defer ts.Close()
+
+	if _, err := newTestAPI(ts).AdjustInventoryLevel(1, 2, 3); err == nil {
+		t.Errorf("expected an error for a 422")
+	}
+}
+
+func TestVariantInventoryLevels(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin/locations.json":
+			w.Write([]byte(`{"locations":[{"id":487838322},{"id":905684977}]}`))
+		case "/admin/api/2021-07/inventory_levels.json":
+			q := r.URL.Query()
+			if q.Get("inventory_item_ids") != "808950810" || q.Get("location_ids") != "487838322,905684977" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"inventory_levels":[{"inventory_item_id":808950810,"location_id":487838322,"available":9},{"inventory_item_id":808950810,"location_id":905684977,"available":1}]}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	api := newTestAPI(ts)
+	variant := &Variant{ID: 39072856, InventoryItemID: 808950810, api: api}
+
+	levels, err := variant.InventoryLevels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(levels) != 2 || levels[0].Available != 9 || levels[1].LocationID != 905684977 {
+		t.Errorf("unexpected levels %+v", levels)
+	}
+
+	if _, err := api.VariantInventoryLevels(&Variant{ID: 39072856}); err == nil {
+		t.Errorf("expected an error for a variant without an inventory item")
+	}
+
+	if _, err := (&Variant{ID: 39072856}).InventoryLevels(); err == nil || err.Error() != "Variant has no API" {
+		t.Errorf("expected a missing API error, got %v", err)
+	}
+	var location *Location
+	if _, err := location.InventoryLevels(); err == nil || err.Error() != "Location is nil" {
+		t.Errorf("expected a nil location error, got %v", err)
+	}
+}
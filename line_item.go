@@ -6,6 +6,7 @@ type LineItem struct {
 	FulfillmentService string               `json:"fulfillment_service,omitempty"`
 	GiftCard           bool                 `json:"gift_card,omitempty"`
 	Grams              int64                `json:"grams,omitempty"`
+	Id                 int64                `json:"id,omitempty"`
 	LinePrice          string               `json:"line_price,omitempty"`
 	Price              string               `json:"price,omitempty"`
 	ProductId          int64                `json:"product_id,omitempty"`
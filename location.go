@@ -64,8 +64,8 @@ func (api *API) Locations() ([]Location, error) {
 		return nil, err
 	}
 
-	for _, v := range result {
-		v.api = api
+	for i := range result {
+		result[i].api = api
 	}
 
 	return result, nil
@@ -0,0 +1,103 @@
+package shopify
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// currencyDecimals lists the ISO 4217 currencies whose minor unit isn't
+// hundredths.
+var currencyDecimals = map[string]int{
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0,
+	"KRW": 0, "PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0,
+	"XOF": 0, "XPF": 0,
+}
+
+// CurrencyDecimals returns the number of decimal places in amounts of
+// currency, e.g. 2 for USD, 3 for KWD and 0 for JPY. Unknown currencies
+// are assumed to have 2.
+func CurrencyDecimals(currency string) int {
+	if decimals, ok := currencyDecimals[strings.ToUpper(currency)]; ok {
+		return decimals
+	}
+	return 2
+}
+
+// parseCents converts a decimal amount such as "10.5" into whole cents so
+// amounts can be added and compared exactly.
+func parseCents(amount string) (int64, error) {
+	return parseMinorUnits(amount, 2)
+}
+
+// formatCents is the inverse of parseCents.
+func formatCents(cents int64) string {
+	return formatMinorUnits(cents, 2)
+}
+
+// parseMinorUnits converts a decimal amount into whole minor units of a
+// currency with decimals places, e.g. "1.5" is 1500 with 3. Amounts finer
+// than the minor unit are an error.
+func parseMinorUnits(amount string, decimals int) (int64, error) {
+	original := amount
+	amount = strings.TrimSpace(amount)
+	if amount == "" {
+		return 0, nil
+	}
+
+	negative := strings.HasPrefix(amount, "-")
+	amount = strings.TrimPrefix(amount, "-")
+
+	whole, frac := amount, ""
+	if i := strings.Index(amount, "."); i >= 0 {
+		whole, frac = amount[:i], amount[i+1:]
+	}
+	if whole == "" && frac == "" || !isDigits(whole) || !isDigits(frac) {
+		return 0, fmt.Errorf("Invalid amount: %s", original)
+	}
+
+	if len(frac) > decimals {
+		if strings.Trim(frac[decimals:], "0") != "" {
+			return 0, fmt.Errorf("Invalid amount: %s", original)
+		}
+		frac = frac[:decimals]
+	}
+	frac += strings.Repeat("0", decimals-len(frac))
+
+	var result int64
+	for _, c := range whole + frac {
+		if result > (math.MaxInt64-int64(c-'0'))/10 {
+			return 0, fmt.Errorf("Invalid amount: %s", original)
+		}
+		result = result*10 + int64(c-'0')
+	}
+
+	if negative {
+		result = -result
+	}
+	return result, nil
+}
+
+// formatMinorUnits is the inverse of parseMinorUnits.
+func formatMinorUnits(units int64, decimals int) string {
+	sign := ""
+	if units < 0 {
+		sign = "-"
+		units = -units
+	}
+	if decimals == 0 {
+		return fmt.Sprintf("%s%d", sign, units)
+	}
+	scale := int64(math.Pow10(decimals))
+	return fmt.Sprintf("%s%d.%0*d", sign, units/scale, decimals, units%scale)
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,46 @@
+package shopify
+
+import (
+	"testing"
+)
+
+func TestParseCents(t *testing.T) {
+	cases := map[string]int64{
+		"10.00": 1000,
+		"10.5":  1050,
+		"0.07":  7,
+		"3":     300,
+		"-1.25": -125,
+		"2.500": 250,
+		"":      0,
+	}
+	for amount, expected := range cases {
+		cents, err := parseCents(amount)
+		if err != nil || cents != expected {
+			t.Errorf("parseCents(%q) = %d, %v; expected %d", amount, cents, err, expected)
+		}
+	}
+
+	for _, amount := range []string{"1.005", "1.-5", "1.+5", "+1", "-", ".", "1.2.3", "1,00", "ten"} {
+		if _, err := parseCents(amount); err == nil {
+			t.Errorf("expected an error for %q", amount)
+		}
+	}
+
+	if units, err := parseMinorUnits("1.005", CurrencyDecimals("KWD")); err != nil || units != 1005 {
+		t.Errorf("expected 1005 fils, got %d, %v", units, err)
+	}
+	if units, err := parseMinorUnits("1500", CurrencyDecimals("JPY")); err != nil || units != 1500 {
+		t.Errorf("expected 1500 yen, got %d, %v", units, err)
+	}
+	if _, err := parseMinorUnits("1500.5", CurrencyDecimals("JPY")); err == nil {
+		t.Errorf("expected an error for fractional yen")
+	}
+	if formatMinorUnits(1005, 3) != "1.005" || formatMinorUnits(-1500, 0) != "-1500" {
+		t.Errorf("formatMinorUnits did not round trip")
+	}
+
+	if formatCents(-125) != "-1.25" || formatCents(7) != "0.07" {
+		t.Errorf("formatCents did not round trip")
+	}
+}
@@ -3,98 +3,248 @@ package shopify
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
+)
+
+var (
+	ErrOrderAlreadyCancelled = errors.New("order has already been cancelled")
+	ErrOrderFulfilled        = errors.New("order has been fulfilled")
+	ErrOrderAlreadyClosed    = errors.New("order is already closed")
+	ErrOrderAlreadyOpen      = errors.New("order is already open")
+	ErrOrderRefundFailed     = errors.New("order refund failed")
+)
+
+// orderActionErrors maps phrases in Shopify's 422 messages to the errors
+// returned for them. Phrases only match whole words, so "has been
+// fulfilled" doesn't match "has been unfulfilled". Order matters: the first
+// match wins.
+var orderActionErrors = []struct {
+	phrase string
+	err    error
+}{
+	{"already been cancelled", ErrOrderAlreadyCancelled},
+	{"already cancelled", ErrOrderAlreadyCancelled},
+	{"has been fulfilled", ErrOrderFulfilled},
+	{"have been fulfilled", ErrOrderFulfilled},
+	{"already fulfilled", ErrOrderFulfilled},
+	{"already been fulfilled", ErrOrderFulfilled},
+	{"already closed", ErrOrderAlreadyClosed},
+	{"already open", ErrOrderAlreadyOpen},
+	{"is not closed", ErrOrderAlreadyOpen},
+	{"refund failed", ErrOrderRefundFailed},
+	{"refund could not be", ErrOrderRefundFailed},
+	{"unable to refund", ErrOrderRefundFailed},
+	{"cannot be refunded", ErrOrderRefundFailed},
+}
+
+// orderActionError returns the error for a 422 message, or nil if none of
+// the phrases match.
+func orderActionError(message string) error {
+	words := " " + strings.Join(strings.FieldsFunc(strings.ToLower(message), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	}), " ") + " "
+	for _, e := range orderActionErrors {
+		if strings.Contains(words, " "+e.phrase+" ") {
+			return e.err
+		}
+	}
+	return nil
+}
+
+type FinancialStatus string
+
+const (
+	FinancialStatusPending           FinancialStatus = "pending"
+	FinancialStatusAuthorized        FinancialStatus = "authorized"
+	FinancialStatusPartiallyPaid     FinancialStatus = "partially_paid"
+	FinancialStatusPaid              FinancialStatus = "paid"
+	FinancialStatusPartiallyRefunded FinancialStatus = "partially_refunded"
+	FinancialStatusRefunded          FinancialStatus = "refunded"
+	FinancialStatusVoided            FinancialStatus = "voided"
+)
+
+// Filter-only financial statuses for OrderOptions.
+const (
+	FinancialStatusAny    FinancialStatus = "any"
+	FinancialStatusUnpaid FinancialStatus = "unpaid"
+)
+
+// FulfillmentStatus is empty for orders with nothing fulfilled yet.
+type FulfillmentStatus string
+
+const (
+	FulfillmentStatusFulfilled FulfillmentStatus = "fulfilled"
+	FulfillmentStatusPartial   FulfillmentStatus = "partial"
+	FulfillmentStatusRestocked FulfillmentStatus = "restocked"
+)
+
+// Filter-only fulfillment statuses for OrderOptions.
+const (
+	FulfillmentStatusShipped     FulfillmentStatus = "shipped"
+	FulfillmentStatusUnshipped   FulfillmentStatus = "unshipped"
+	FulfillmentStatusUnfulfilled FulfillmentStatus = "unfulfilled"
+	FulfillmentStatusAny         FulfillmentStatus = "any"
+)
+
+type CancelReason string
+
+const (
+	CancelReasonCustomer  CancelReason = "customer"
+	CancelReasonFraud     CancelReason = "fraud"
+	CancelReasonInventory CancelReason = "inventory"
+	CancelReasonDeclined  CancelReason = "declined"
+	CancelReasonOther     CancelReason = "other"
 )
 
 type Order struct {
-	BuyerAcceptsMarketing bool           `json:"buyer_accepts_marketing,omitempty"`
-	CancelReason          string         `json:"cancel_reason,omitempty"`
-	CancelledAt           string         `json:"cancelled_at,omitempty"`
-	CartToken             string         `json:"cart_token,omitempty"`
-	CheckoutToken         string         `json:"checkout_token,omitempty"`
-	ClosedAt              string         `json:"closed_at,omitempty"`
-	Confirmed             bool           `json:"confirmed,omitempty"`
-	CreatedAt             time.Time      `json:"created_at,omitempty"`
-	Currency              string         `json:"currency,omitempty"`
-	Email                 string         `json:"email,omitempty"`
-	FinancialStatus       string         `json:"financial_status,omitempty"`
-	FulfillmentStatus     string         `json:"fulfillment_status,omitempty"`
-	Gateway               string         `json:"gateway,omitempty"`
-	Id                    int64          `json:"id,omitempty"`
-	LandingSite           string         `json:"landing_site,omitempty"`
-	LocationId            int64          `json:"location_id,omitempty"`
-	Name                  string         `json:"name,omitempty"`
-	Note                  string         `json:"note,omitempty"`
-	Number                int64          `json:"number,omitempty"`
-	ProcessedAt           time.Time      `json:"processed_at,omitempty"`
-	Reference             string         `json:"reference,omitempty"`
-	ReferringSite         string         `json:"referring_site,omitempty"`
-	SourceIdentifier      string         `json:"source_identifier,omitempty"`
-	SourceName            string         `json:"source_name,omitempty"`
-	SourceUrl             string         `json:"source_url,omitempty"`
-	SubtotalPrice         string         `json:"subtotal_price,omitempty"`
-	TaxesIncluded         bool           `json:"taxes_included,omitempty"`
-	Test                  bool           `json:"test,omitempty"`
-	Token                 string         `json:"token,omitempty"`
-	TotalDiscounts        string         `json:"total_discounts,omitempty"`
-	TotalLineItemsPrice   string         `json:"total_line_items_price,omitempty"`
-	TotalPrice            string         `json:"total_price,omitempty"`
-	TotalPriceUsd         string         `json:"total_price_usd,omitempty"`
-	TotalTax              string         `json:"total_tax,omitempty"`
-	TotalWeight           int64          `json:"total_weight,omitempty"`
-	UpdatedAt             time.Time      `json:"updated_at,omitempty"`
-	UserId                int64          `json:"user_id,omitempty"`
-	BrowserIp             string         `json:"browser_ip,omitempty"`
-	LandingSiteRef        string         `json:"landing_site_ref,omitempty"`
-	OrderNumber           int64          `json:"order_number,omitempty"`
-	DiscountCodes         []interface{}  `json:"discount_codes,omitempty"`
-	NoteAttributes        []interface{}  `json:"note_attributes,omitempty"`
-	ProcessingMethod      string         `json:"processing_method,omitempty"`
-	Source                string         `json:"source,omitempty"`
-	CheckoutId            int64          `json:"checkout_id,omitempty"`
-	TaxLines              []interface{}  `json:"tax_lines,omitempty"`
-	Tags                  string         `json:"tags,omitempty"`
-	LineItems             []LineItem     `json:"line_items,omitempty"`
-	ShippingLines         []ShippingLine `json:"shipping_lines,omitempty"`
-	BillingAddress        BillingAddress `json:"billing_address,omitempty"`
-	ShippingAddress       BillingAddress `json:"shipping_address,omitempty"`
-	Fulfillments          []interface{}  `json:"fulfillments,omitempty"`
-	ClientDetails         ClientDetail   `json:"client_details,omitempty"`
-	Refunds               []interface{}  `json:"refunds,omitempty"`
-	Customer              Customer       `json:"customer,omitempty"`
-
-	api *API
+	BuyerAcceptsMarketing bool              `json:"buyer_accepts_marketing,omitempty"`
+	CancelReason          CancelReason      `json:"cancel_reason,omitempty"`
+	CancelledAt           string            `json:"cancelled_at,omitempty"`
+	CartToken             string            `json:"cart_token,omitempty"`
+	CheckoutToken         string            `json:"checkout_token,omitempty"`
+	ClosedAt              string            `json:"closed_at,omitempty"`
+	Confirmed             bool              `json:"confirmed,omitempty"`
+	CreatedAt             time.Time         `json:"created_at,omitempty"`
+	Currency              string            `json:"currency,omitempty"`
+	Email                 string            `json:"email,omitempty"`
+	FinancialStatus       FinancialStatus   `json:"financial_status,omitempty"`
+	FulfillmentStatus     FulfillmentStatus `json:"fulfillment_status,omitempty"`
+	Gateway               string            `json:"gateway,omitempty"`
+	Id                    int64             `json:"id,omitempty"`
+	LandingSite           string            `json:"landing_site,omitempty"`
+	LocationId            int64             `json:"location_id,omitempty"`
+	Name                  string            `json:"name,omitempty"`
+	Note                  string            `json:"note,omitempty"`
+	Number                int64             `json:"number,omitempty"`
+	ProcessedAt           time.Time         `json:"processed_at,omitempty"`
+	Reference             string            `json:"reference,omitempty"`
+	ReferringSite         string            `json:"referring_site,omitempty"`
+	SourceIdentifier      string            `json:"source_identifier,omitempty"`
+	SourceName            string            `json:"source_name,omitempty"`
+	SourceUrl             string            `json:"source_url,omitempty"`
+	SubtotalPrice         string            `json:"subtotal_price,omitempty"`
+	TaxesIncluded         bool              `json:"taxes_included,omitempty"`
+	Test                  bool              `json:"test,omitempty"`
+	Token                 string            `json:"token,omitempty"`
+	TotalDiscounts        string            `json:"total_discounts,omitempty"`
+	TotalLineItemsPrice   string            `json:"total_line_items_price,omitempty"`
+	TotalPrice            string            `json:"total_price,omitempty"`
+	TotalPriceUsd         string            `json:"total_price_usd,omitempty"`
+	TotalTax              string            `json:"total_tax,omitempty"`
+	TotalWeight           int64             `json:"total_weight,omitempty"`
+	UpdatedAt             time.Time         `json:"updated_at,omitempty"`
+	UserId                int64             `json:"user_id,omitempty"`
+	BrowserIp             string            `json:"browser_ip,omitempty"`
+	LandingSiteRef        string            `json:"landing_site_ref,omitempty"`
+	OrderNumber           int64             `json:"order_number,omitempty"`
+	DiscountCodes         []interface{}     `json:"discount_codes,omitempty"`
+	NoteAttributes        []interface{}     `json:"note_attributes,omitempty"`
+	ProcessingMethod      string            `json:"processing_method,omitempty"`
+	Source                string            `json:"source,omitempty"`
+	CheckoutId            int64             `json:"checkout_id,omitempty"`
+	TaxLines              []interface{}     `json:"tax_lines,omitempty"`
+	Tags                  string            `json:"tags,omitempty"`
+	LineItems             []LineItem        `json:"line_items,omitempty"`
+	ShippingLines         []ShippingLine    `json:"shipping_lines,omitempty"`
+	BillingAddress        BillingAddress    `json:"billing_address,omitempty"`
+	ShippingAddress       BillingAddress    `json:"shipping_address,omitempty"`
+	Fulfillments          []Fulfillment     `json:"fulfillments,omitempty"`
+	ClientDetails         ClientDetail      `json:"client_details,omitempty"`
+	Refunds               []Refund          `json:"refunds,omitempty"`
+	Customer              Customer          `json:"customer,omitempty"`
+
+	api    *API
+	fields []string
 }
 
+// OrderStatus filters orders by whether they are open, closed or cancelled.
+type OrderStatus string
+
+const (
+	OrderStatusOpen      OrderStatus = "open"
+	OrderStatusClosed    OrderStatus = "closed"
+	OrderStatusCancelled OrderStatus = "cancelled"
+	OrderStatusAny       OrderStatus = "any"
+)
+
 type OrderOptions struct {
-	IDs          string `url:"ids,omitempty"`
-	Limit        int    `url:"limit,omitempty"`
-	Page         int    `url:"page,omitempty"`
-	SinceID      int64  `url:"since_id,omitempty"`
-	CollectionID string `url:"collection_id,omitempty"`
-	CreatedAtMin string `url:"created_at_min,omitempty"`
-	CreatedAtMax string `url:"created_at_max,omitempty"`
-	UpdatedAtMin string `url:"updated_at_min,omitempty"`
-	UpdatedAtMax string `url:"updated_at_max,omitempty"`
-	Fields       string `url:"fields,omitempty"`
-}
-
-func (api *API) Orders() ([]Order, error) {
+	IDs               string            `url:"ids,omitempty"`
+	Limit             int               `url:"limit,omitempty"`
+	SinceID           int64             `url:"since_id,omitempty"`
+	CreatedAtMin      string            `url:"created_at_min,omitempty"`
+	CreatedAtMax      string            `url:"created_at_max,omitempty"`
+	UpdatedAtMin      string            `url:"updated_at_min,omitempty"`
+	UpdatedAtMax      string            `url:"updated_at_max,omitempty"`
+	ProcessedAtMin    string            `url:"processed_at_min,omitempty"`
+	ProcessedAtMax    string            `url:"processed_at_max,omitempty"`
+	AttributionAppID  string            `url:"attribution_app_id,omitempty"`
+	Status            OrderStatus       `url:"status,omitempty"`
+	FinancialStatus   FinancialStatus   `url:"financial_status,omitempty"`
+	FulfillmentStatus FulfillmentStatus `url:"fulfillment_status,omitempty"`
+	// Fields limits the response to these order fields, named as in
+	// Order's json tags.
+	Fields []string `url:"fields,omitempty,comma"`
+}
+
+type OrdersCountOptions struct {
+	CreatedAtMin      string            `url:"created_at_min,omitempty"`
+	CreatedAtMax      string            `url:"created_at_max,omitempty"`
+	UpdatedAtMin      string            `url:"updated_at_min,omitempty"`
+	UpdatedAtMax      string            `url:"updated_at_max,omitempty"`
+	ProcessedAtMin    string            `url:"processed_at_min,omitempty"`
+	ProcessedAtMax    string            `url:"processed_at_max,omitempty"`
+	AttributionAppID  string            `url:"attribution_app_id,omitempty"`
+	Status            OrderStatus       `url:"status,omitempty"`
+	FinancialStatus   FinancialStatus   `url:"financial_status,omitempty"`
+	FulfillmentStatus FulfillmentStatus `url:"fulfillment_status,omitempty"`
+}
+
+func (api *API) Orders() ([]Order, *Pages, error) {
 	return api.OrdersWithOptions(&OrderOptions{})
 }
 
-func (api *API) OrdersWithOptions(options *OrderOptions) ([]Order, error) {
+// OrdersWithOptions returns the first page of matching orders. Only open
+// orders are returned unless Status is set. When Fields is set, orders only
+// report HasField for the projected fields.
+func (api *API) OrdersWithOptions(options *OrderOptions) ([]Order, *Pages, error) {
+	var fields []string
+	if options != nil {
+		fields = options.Fields
+		if err := checkOrderFields(fields); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	qs := encodeOptions(options)
-	endpoint := fmt.Sprintf("/admin/orders.json?%v", qs)
-	res, status, err := api.request(endpoint, "GET", nil, nil)
+	endpoint := fmt.Sprintf("BASE_PATH/orders.json?%v", qs)
 
+	res, status, pages, err := api.requestWithPagination(endpoint, "GET", nil, nil)
+	return api.processOrdersResponse(fields, res, status, pages, err)
+}
+
+// OrdersFromPages returns the next page of orders, keeping the original
+// options' fields projection.
+func (api *API) OrdersFromPages(pages *Pages, fields []string) ([]Order, *Pages, error) {
+	if pages.HasNextPage() {
+		res, status, pages, err := api.getNextPage(pages)
+		return api.processOrdersResponse(fields, res, status, pages, err)
+	}
+	return nil, &Pages{}, fmt.Errorf("No next page")
+}
+
+func (api *API) processOrdersResponse(fields []string, res *bytes.Buffer, status int, pages *Pages, err error) ([]Order, *Pages, error) {
 	if err != nil {
-		return nil, err
+		return nil, pages, err
 	}
 
 	if status != 200 {
-		return nil, fmt.Errorf("Status returned: %d", status)
+		return nil, pages, fmt.Errorf("Status returned: %d", status)
 	}
 
 	r := &map[string][]Order{}
@@ -103,16 +253,80 @@ func (api *API) OrdersWithOptions(options *OrderOptions) ([]Order, error) {
 	result := (*r)["orders"]
 
 	if err != nil {
-		return nil, err
+		return nil, pages, err
 	}
 
-	for _, v := range result {
-		v.api = api
+	for i := range result {
+		result[i].setAPI(api)
+		result[i].fields = fields
 	}
 
+	return result, pages, nil
+}
+
+func (api *API) OrdersCount(options *OrdersCountOptions) (int, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("BASE_PATH/orders/count.json?%v", qs)
+
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if status != 200 {
+		return 0, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := map[string]interface{}{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	result, _ := strconv.Atoi(fmt.Sprintf("%v", r["count"]))
+	if err != nil {
+		return 0, err
+	}
 	return result, nil
 }
 
+// HasField reports whether the order was loaded with the named field. It is
+// false for fields left out of a projection, whose values are zero rather
+// than empty.
+func (obj *Order) HasField(name string) bool {
+	if obj.fields == nil {
+		return true
+	}
+	for _, field := range obj.fields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+var orderFieldNames = jsonFieldNames(reflect.TypeOf(Order{}))
+
+// checkOrderFields rejects projections naming fields Order can't decode,
+// which would otherwise come back silently empty.
+func checkOrderFields(fields []string) error {
+	for _, field := range fields {
+		if !orderFieldNames[field] {
+			return fmt.Errorf("Unknown order field: %s", field)
+		}
+	}
+	return nil
+}
+
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
 func (api *API) Order(id int64) (*Order, error) {
 	endpoint := fmt.Sprintf("/admin/orders/%d.json", id)
 
@@ -134,11 +348,22 @@ func (api *API) Order(id int64) (*Order, error) {
 		return nil, err
 	}
 
-	result.api = api
+	result.setAPI(api)
 
 	return &result, nil
 }
 
+// setAPI binds the order and its nested resources to api.
+func (obj *Order) setAPI(api *API) {
+	obj.api = api
+	for i := range obj.Fulfillments {
+		obj.Fulfillments[i].api = api
+	}
+	for i := range obj.Refunds {
+		obj.Refunds[i].api = api
+	}
+}
+
 func (api *API) NewOrder() *Order {
 	return &Order{api: api}
 }
@@ -187,7 +412,128 @@ func (obj *Order) Save() error {
 		return err
 	}
 
+	api := obj.api
 	*obj = r["order"]
+	obj.setAPI(api)
+
+	return nil
+}
+
+// OrderCancelOptions are sent with Order.Cancel. Set Amount and Currency to
+// refund part of the order, or Refund for a full refund request; they can't
+// be combined.
+type OrderCancelOptions struct {
+	Reason   CancelReason   `json:"reason,omitempty"`
+	Email    bool           `json:"email,omitempty"`
+	Restock  bool           `json:"restock,omitempty"`
+	Amount   string         `json:"amount,omitempty"`
+	Currency string         `json:"currency,omitempty"`
+	Refund   *RefundRequest `json:"refund,omitempty"`
+}
+
+// CancelOrder cancels an order and returns its new state. Orders with
+// fulfillments or that are already cancelled return ErrOrderFulfilled or
+// ErrOrderAlreadyCancelled.
+func (api *API) CancelOrder(id int64, options *OrderCancelOptions) (*Order, error) {
+	if options == nil {
+		options = &OrderCancelOptions{}
+	}
+	return api.orderAction(id, "cancel", options)
+}
 
+func (api *API) CloseOrder(id int64) (*Order, error) {
+	return api.orderAction(id, "close", map[string]interface{}{})
+}
+
+// OpenOrder reopens a closed order.
+func (api *API) OpenOrder(id int64) (*Order, error) {
+	return api.orderAction(id, "open", map[string]interface{}{})
+}
+
+func (obj *Order) Cancel(options *OrderCancelOptions) error {
+	return obj.replace(obj.api.CancelOrder(obj.Id, options))
+}
+
+func (obj *Order) Close() error {
+	return obj.replace(obj.api.CloseOrder(obj.Id))
+}
+
+func (obj *Order) Open() error {
+	return obj.replace(obj.api.OpenOrder(obj.Id))
+}
+
+func (obj *Order) replace(updated *Order, err error) error {
+	if err != nil {
+		return err
+	}
+	api := obj.api
+	*obj = *updated
+	obj.setAPI(api)
 	return nil
 }
+
+func (api *API) orderAction(id int64, action string, body interface{}) (*Order, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/orders/%d/%s.json", id, action)
+	method := "POST"
+	expectedStatus := 200
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, status, err := api.request(endpoint, method, nil, buf)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != expectedStatus {
+		r := map[string]interface{}{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err != nil {
+			return nil, fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+		}
+
+		if status == 422 {
+			if err := orderActionError(errorMessages(r["error"], r["errors"])); err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, fmt.Errorf("Status %d: %v", status, r)
+	}
+
+	r := map[string]Order{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := r["order"]
+	result.setAPI(api)
+
+	return &result, nil
+}
+
+// errorMessages flattens the strings in Shopify error values, which may be
+// a string, a list or a map of field names to lists.
+func errorMessages(values ...interface{}) string {
+	messages := []string{}
+	for _, value := range values {
+		switch v := value.(type) {
+		case string:
+			messages = append(messages, v)
+		case []interface{}:
+			messages = append(messages, errorMessages(v...))
+		case map[string]interface{}:
+			for _, nested := range v {
+				messages = append(messages, errorMessages(nested))
+			}
+		}
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,117 @@
+package shopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOrderCancel(t *testing.T) {
+	var sent map[string]interface{}
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/api/2021-07/orders/450789469/cancel.json" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.Write([]byte(`{"order":{"id":450789469,"cancel_reason":"customer","financial_status":"voided","cancelled_at":"2021-07-01T12:00:00-04:00"}}`))
+	}))
+	defer ts.Close()
+
+	order := newTestAPI(ts).NewOrder()
+	order.Id = 450789469
+	err := order.Cancel(&OrderCancelOptions{Reason: CancelReasonCustomer, Email: true, Restock: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sent["reason"] != "customer" || sent["email"] != true || sent["restock"] != true {
+		t.Errorf("unexpected body %v", sent)
+	}
+	if order.FinancialStatus != FinancialStatusVoided || order.CancelReason != CancelReasonCustomer {
+		t.Errorf("order not updated: %+v", order)
+	}
+	if order.api == nil {
+		t.Errorf("order lost its api")
+	}
+}
+
+func TestOrderActionErrors(t *testing.T) {
+	cases := []struct {
+		body     string
+		expected error
+	}{
+		{`{"error":"Cannot cancel an order that has already been cancelled"}`, ErrOrderAlreadyCancelled},
+		{`{"errors":{"base":["Cannot cancel an order that has been fulfilled"]}}`, ErrOrderFulfilled},
+		{`{"errors":"Order is already closed"}`, ErrOrderAlreadyClosed},
+	}
+
+	for _, c := range cases {
+		ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(422)
+			w.Write([]byte(c.body))
+		}))
+
+		_, err := newTestAPI(ts).CancelOrder(1, nil)
+		if err != c.expected {
+			t.Errorf("%s: expected %v, got %v", c.body, c.expected, err)
+		}
+		ts.Close()
+	}
+}
+
+func TestOrderActionErrorPhrases(t *testing.T) {
+	cases := []struct {
+		message  string
+		expected error
+	}{
+		{"Cannot cancel an order that has already been cancelled", ErrOrderAlreadyCancelled},
+		{"Cannot cancel an order that has been fulfilled", ErrOrderFulfilled},
+		{"Order has already been fulfilled.", ErrOrderFulfilled},
+		{"Cannot cancel an order with unfulfilled items", nil},
+		{"Line items have been unfulfilled", nil},
+		{"Order is already closed", ErrOrderAlreadyClosed},
+		{"Order is not closed", ErrOrderAlreadyOpen},
+		{"Refund failed: card declined", ErrOrderRefundFailed},
+		{"Refunded amount exceeds the total", nil},
+		{"Cannot cancel an order with refunds pending", nil},
+	}
+
+	for _, c := range cases {
+		if err := orderActionError(c.message); err != c.expected {
+			t.Errorf("%q: expected %v, got %v", c.message, c.expected, err)
+		}
+	}
+}
+
+func TestOrdersWithOptionsProjection(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("status") != "any" || q.Get("financial_status") != "paid" || q.Get("fields") != "id,total_price" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"orders":[{"id":1,"total_price":"10.00"}]}`))
+	}))
+	defer ts.Close()
+
+	api := newTestAPI(ts)
+	orders, _, err := api.OrdersWithOptions(&OrderOptions{
+		Status:          OrderStatusAny,
+		FinancialStatus: FinancialStatusPaid,
+		Fields:          []string{"id", "total_price"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orders) != 1 || orders[0].TotalPrice != "10.00" {
+		t.Fatalf("unexpected orders %+v", orders)
+	}
+	if !orders[0].HasField("total_price") || orders[0].HasField("email") {
+		t.Errorf("projection not recorded")
+	}
+
+	_, _, err = api.OrdersWithOptions(&OrderOptions{Fields: []string{"totl_price"}})
+	if err == nil {
+		t.Errorf("expected an error for an unknown field")
+	}
+}
@@ -0,0 +1,274 @@
+package shopify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+type PriceRuleTargetType string
+
+const (
+	PriceRuleTargetLineItem     PriceRuleTargetType = "line_item"
+	PriceRuleTargetShippingLine PriceRuleTargetType = "shipping_line"
+)
+
+type PriceRuleTargetSelection string
+
+const (
+	PriceRuleTargetSelectionAll      PriceRuleTargetSelection = "all"
+	PriceRuleTargetSelectionEntitled PriceRuleTargetSelection = "entitled"
+)
+
+type PriceRuleAllocationMethod string
+
+const (
+	PriceRuleAllocationEach   PriceRuleAllocationMethod = "each"
+	PriceRuleAllocationAcross PriceRuleAllocationMethod = "across"
+)
+
+type PriceRuleValueType string
+
+const (
+	PriceRuleValueFixedAmount PriceRuleValueType = "fixed_amount"
+	PriceRuleValuePercentage  PriceRuleValueType = "percentage"
+)
+
+type PriceRuleCustomerSelection string
+
+const (
+	PriceRuleCustomerSelectionAll          PriceRuleCustomerSelection = "all"
+	PriceRuleCustomerSelectionPrerequisite PriceRuleCustomerSelection = "prerequisite"
+)
+
+// PriceRuleAmountRange is a minimum subtotal or shipping price.
+type PriceRuleAmountRange struct {
+	GreaterThanOrEqualTo string `json:"greater_than_or_equal_to,omitempty"`
+	LessThanOrEqualTo    string `json:"less_than_or_equal_to,omitempty"`
+}
+
+type PriceRuleQuantityRange struct {
+	GreaterThanOrEqualTo int64 `json:"greater_than_or_equal_to,omitempty"`
+}
+
+// PriceRuleQuantityRatio sets up "buy X get Y" rules.
+type PriceRuleQuantityRatio struct {
+	PrerequisiteQuantity int64 `json:"prerequisite_quantity,omitempty"`
+	EntitledQuantity     int64 `json:"entitled_quantity,omitempty"`
+}
+
+type PriceRulePurchase struct {
+	PrerequisiteAmount string `json:"prerequisite_amount,omitempty"`
+}
+
+type PriceRule struct {
+	ID                                     int64                      `json:"id,omitempty"`
+	Title                                  string                     `json:"title,omitempty"`
+	TargetType                             PriceRuleTargetType        `json:"target_type,omitempty"`
+	TargetSelection                        PriceRuleTargetSelection   `json:"target_selection,omitempty"`
+	AllocationMethod                       PriceRuleAllocationMethod  `json:"allocation_method,omitempty"`
+	AllocationLimit                        int64                      `json:"allocation_limit,omitempty"`
+	ValueType                              PriceRuleValueType         `json:"value_type,omitempty"`
+	Value                                  string                     `json:"value,omitempty"`
+	OncePerCustomer                        bool                       `json:"once_per_customer,omitempty"`
+	UsageLimit                             int64                      `json:"usage_limit,omitempty"`
+	CustomerSelection                      PriceRuleCustomerSelection `json:"customer_selection,omitempty"`
+	PrerequisiteCustomerIDs                []int64                    `json:"prerequisite_customer_ids,omitempty"`
+	PrerequisiteSavedSearchIDs             []int64                    `json:"prerequisite_saved_search_ids,omitempty"`
+	PrerequisiteProductIDs                 []int64                    `json:"prerequisite_product_ids,omitempty"`
+	PrerequisiteVariantIDs                 []int64                    `json:"prerequisite_variant_ids,omitempty"`
+	PrerequisiteCollectionIDs              []int64                    `json:"prerequisite_collection_ids,omitempty"`
+	PrerequisiteSubtotalRange              *PriceRuleAmountRange      `json:"prerequisite_subtotal_range,omitempty"`
+	PrerequisiteQuantityRange              *PriceRuleQuantityRange    `json:"prerequisite_quantity_range,omitempty"`
+	PrerequisiteShippingPriceRange         *PriceRuleAmountRange      `json:"prerequisite_shipping_price_range,omitempty"`
+	PrerequisiteToEntitlementQuantityRatio *PriceRuleQuantityRatio    `json:"prerequisite_to_entitlement_quantity_ratio,omitempty"`
+	PrerequisiteToEntitlementPurchase      *PriceRulePurchase         `json:"prerequisite_to_entitlement_purchase,omitempty"`
+	EntitledProductIDs                     []int64                    `json:"entitled_product_ids,omitempty"`
+	EntitledVariantIDs                     []int64                    `json:"entitled_variant_ids,omitempty"`
+	EntitledCollectionIDs                  []int64                    `json:"entitled_collection_ids,omitempty"`
+	EntitledCountryIDs                     []int64                    `json:"entitled_country_ids,omitempty"`
+	StartsAt                               string                     `json:"starts_at,omitempty"`
+	EndsAt                                 string                     `json:"ends_at,omitempty"`
+	CreatedAt                              string                     `json:"created_at,omitempty"`
+	UpdatedAt                              string                     `json:"updated_at,omitempty"`
+
+	api *API
+}
+
+type PriceRuleOptions struct {
+	Limit        int    `url:"limit,omitempty"`
+	SinceID      int64  `url:"since_id,omitempty"`
+	CreatedAtMin string `url:"created_at_min,omitempty"`
+	CreatedAtMax string `url:"created_at_max,omitempty"`
+	UpdatedAtMin string `url:"updated_at_min,omitempty"`
+	UpdatedAtMax string `url:"updated_at_max,omitempty"`
+	StartsAtMin  string `url:"starts_at_min,omitempty"`
+	StartsAtMax  string `url:"starts_at_max,omitempty"`
+	EndsAtMin    string `url:"ends_at_min,omitempty"`
+	EndsAtMax    string `url:"ends_at_max,omitempty"`
+	TimesUsed    int64  `url:"times_used,omitempty"`
+}
+
+func (api *API) PriceRules(options *PriceRuleOptions) ([]*PriceRule, *Pages, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("BASE_PATH/price_rules.json?%v", qs)
+	return api.processPriceRulesResponse(api.requestWithPagination(endpoint, "GET", nil, nil))
+}
+
+func (api *API) PriceRulesFromPages(pages *Pages) ([]*PriceRule, *Pages, error) {
+	if pages.HasNextPage() {
+		return api.processPriceRulesResponse(api.getNextPage(pages))
+	}
+	return nil, &Pages{}, fmt.Errorf("No next page")
+}
+
+func (api *API) processPriceRulesResponse(res *bytes.Buffer, status int, pages *Pages, err error) ([]*PriceRule, *Pages, error) {
+	if err != nil {
+		return nil, pages, err
+	}
+
+	if status != 200 {
+		return nil, pages, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := &map[string][]*PriceRule{}
+	err = json.NewDecoder(res).Decode(r)
+	if err != nil {
+		return nil, pages, err
+	}
+
+	result := (*r)["price_rules"]
+	for _, v := range result {
+		v.api = api
+	}
+
+	return result, pages, nil
+}
+
+func (api *API) PriceRulesCount() (int, error) {
+	res, status, err := api.request("BASE_PATH/price_rules/count.json", "GET", nil, nil)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if status != 200 {
+		return 0, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := map[string]interface{}{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	result, _ := strconv.Atoi(fmt.Sprintf("%v", r["count"]))
+	if err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+func (api *API) PriceRule(id int64) (*PriceRule, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/price_rules/%d.json", id)
+
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := map[string]PriceRule{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	result := r["price_rule"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.api = api
+
+	return &result, nil
+}
+
+func (api *API) NewPriceRule() *PriceRule {
+	return &PriceRule{api: api}
+}
+
+func (obj *PriceRule) Save() error {
+	endpoint := fmt.Sprintf("BASE_PATH/price_rules/%d.json", obj.ID)
+	method := "PUT"
+	expectedStatus := 200
+
+	if obj.ID == 0 {
+		endpoint = "BASE_PATH/price_rules.json"
+		method = "POST"
+		expectedStatus = 201
+	}
+
+	body := map[string]*PriceRule{}
+	body["price_rule"] = obj
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	res, status, err := obj.api.request(endpoint, method, nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return fmt.Errorf("Status %d: %v", status, r.Errors)
+		}
+
+		return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+	}
+
+	r := map[string]PriceRule{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	api := obj.api
+	*obj = r["price_rule"]
+	obj.api = api
+
+	return nil
+}
+
+func (obj *PriceRule) Delete() error {
+	endpoint := fmt.Sprintf("BASE_PATH/price_rules/%d.json", obj.ID)
+	method := "DELETE"
+	expectedStatus := 204
+
+	res, status, err := obj.api.request(endpoint, method, nil, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return fmt.Errorf("Status %d: %v", status, r.Errors)
+		}
+
+		return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+	}
+
+	return nil
+}
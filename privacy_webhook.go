@@ -0,0 +1,234 @@
+package shopify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	TopicCustomersDataRequest = "customers/data_request"
+	TopicCustomersRedact      = "customers/redact"
+	TopicShopRedact           = "shop/redact"
+)
+
+// Shopify expects mandatory webhooks to be answered within 5 seconds.
+const COMPLIANCE_RESPONSE_TIMEOUT = 4 * time.Second
+
+type PrivacyCustomer struct {
+	ID    int64  `json:"id,omitempty"`
+	Email string `json:"email,omitempty"`
+	Phone string `json:"phone,omitempty"`
+}
+
+type PrivacyDataRequest struct {
+	ID int64 `json:"id,omitempty"`
+}
+
+// CustomersDataRequest is the payload of customers/data_request.
+type CustomersDataRequest struct {
+	ShopID          int64              `json:"shop_id,omitempty"`
+	ShopDomain      string             `json:"shop_domain,omitempty"`
+	OrdersRequested []int64            `json:"orders_requested,omitempty"`
+	Customer        PrivacyCustomer    `json:"customer,omitempty"`
+	DataRequest     PrivacyDataRequest `json:"data_request,omitempty"`
+}
+
+// CustomersRedact is the payload of customers/redact.
+type CustomersRedact struct {
+	ShopID         int64           `json:"shop_id,omitempty"`
+	ShopDomain     string          `json:"shop_domain,omitempty"`
+	Customer       PrivacyCustomer `json:"customer,omitempty"`
+	OrdersToRedact []int64         `json:"orders_to_redact,omitempty"`
+}
+
+// ShopRedact is the payload of shop/redact.
+type ShopRedact struct {
+	ShopID     int64  `json:"shop_id,omitempty"`
+	ShopDomain string `json:"shop_domain,omitempty"`
+}
+
+// ComplianceStatus is the outcome of a mandatory webhook.
+type ComplianceStatus string
+
+const (
+	ComplianceCompleted ComplianceStatus = "completed"
+	ComplianceFailed    ComplianceStatus = "failed"
+
+	// ComplianceUnhandled means no callback was set for the topic, so nothing
+	// was done with the request.
+	ComplianceUnhandled ComplianceStatus = "unhandled"
+)
+
+// ComplianceRecord is the audit entry for one mandatory webhook.
+type ComplianceRecord struct {
+	Topic       string           `json:"topic"`
+	ShopDomain  string           `json:"shop_domain"`
+	WebhookID   string           `json:"webhook_id"`
+	Status      ComplianceStatus `json:"status"`
+	ReceivedAt  time.Time        `json:"received_at"`
+	CompletedAt time.Time        `json:"completed_at"`
+	Error       string           `json:"error,omitempty"`
+}
+
+// ComplianceAuditLog stores a record each time a request has been handled.
+type ComplianceAuditLog interface {
+	Record(record *ComplianceRecord) error
+}
+
+// MemoryComplianceAuditLog keeps compliance records in memory.
+type MemoryComplianceAuditLog struct {
+	mu      sync.Mutex
+	records []*ComplianceRecord
+}
+
+func (l *MemoryComplianceAuditLog) Record(record *ComplianceRecord) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records = append(l.records, record)
+	return nil
+}
+
+func (l *MemoryComplianceAuditLog) Records() []*ComplianceRecord {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]*ComplianceRecord{}, l.records...)
+}
+
+// ErrComplianceUnhandled is recorded for topics without a callback.
+var ErrComplianceUnhandled = errors.New("No callback set for compliance topic")
+
+// ComplianceHandler serves the three mandatory privacy webhooks. Topics
+// without a callback are still answered with 200, so Shopify doesn't retry
+// them, but are audited as ComplianceUnhandled.
+type ComplianceHandler struct {
+	App *App
+
+	OnDataRequest    func(request *CustomersDataRequest) error
+	OnCustomerRedact func(request *CustomersRedact) error
+	OnShopRedact     func(request *ShopRedact) error
+
+	Audit ComplianceAuditLog
+
+	// AuditError is called when Audit fails to store a record. Failures
+	// while the request is still open are also answered with 500 so Shopify
+	// redelivers it.
+	AuditError func(record *ComplianceRecord, err error)
+
+	// Timeout is how long to wait for a callback before answering. Callbacks
+	// that outlive it keep running in the background and are audited when
+	// they finish, but the request has already been acked: if they fail,
+	// Shopify will not redeliver it, so check the audit log for failures.
+	Timeout time.Duration
+}
+
+func (h *ComplianceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	event, status, err := readWebhookEvent(h.App, r)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	record := &ComplianceRecord{
+		Topic:      event.Topic,
+		ShopDomain: event.ShopDomain,
+		WebhookID:  event.WebhookID,
+		ReceivedAt: time.Now(),
+	}
+
+	run, err := h.callback(event)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	timeout := h.Timeout
+	if timeout == 0 {
+		timeout = COMPLIANCE_RESPONSE_TIMEOUT
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		err := run()
+		record.CompletedAt = time.Now()
+		switch {
+		case err == ErrComplianceUnhandled:
+			record.Status = ComplianceUnhandled
+			record.Error = err.Error()
+			err = nil
+		case err != nil:
+			record.Status = ComplianceFailed
+			record.Error = err.Error()
+		default:
+			record.Status = ComplianceCompleted
+		}
+		if auditErr := h.audit(record); auditErr != nil && err == nil {
+			err = auditErr
+		}
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			// Shopify will redeliver the request
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	case <-time.After(timeout):
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *ComplianceHandler) audit(record *ComplianceRecord) error {
+	if h.Audit == nil {
+		return nil
+	}
+	err := h.Audit.Record(record)
+	if err != nil && h.AuditError != nil {
+		h.AuditError(record, err)
+	}
+	return err
+}
+
+// callback decodes the payload and returns the user callback for its topic.
+func (h *ComplianceHandler) callback(event *WebhookEvent) (func() error, error) {
+	switch event.Topic {
+	case TopicCustomersDataRequest:
+		request := &CustomersDataRequest{}
+		if err := json.Unmarshal(event.Body, request); err != nil {
+			return nil, err
+		}
+		if h.OnDataRequest == nil {
+			return skipCompliance, nil
+		}
+		return func() error { return h.OnDataRequest(request) }, nil
+	case TopicCustomersRedact:
+		request := &CustomersRedact{}
+		if err := json.Unmarshal(event.Body, request); err != nil {
+			return nil, err
+		}
+		if h.OnCustomerRedact == nil {
+			return skipCompliance, nil
+		}
+		return func() error { return h.OnCustomerRedact(request) }, nil
+	case TopicShopRedact:
+		request := &ShopRedact{}
+		if err := json.Unmarshal(event.Body, request); err != nil {
+			return nil, err
+		}
+		if h.OnShopRedact == nil {
+			return skipCompliance, nil
+		}
+		return func() error { return h.OnShopRedact(request) }, nil
+	}
+	return nil, fmt.Errorf("Unsupported compliance topic: %s", event.Topic)
+}
+
+func skipCompliance() error {
+	return ErrComplianceUnhandled
+}
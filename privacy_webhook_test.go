@@ -0,0 +1,109 @@
+package shopify
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func postComplianceHook(h *ComplianceHandler, topic string, body []byte) int {
+	req := httptest.NewRequest("POST", "/compliance", bytes.NewReader(body))
+	req.Header.Set("X-Shopify-Topic", topic)
+	req.Header.Set("X-Shopify-Shop-Domain", "burnsmod.myshopify.com")
+	req.Header.Set("X-Shopify-Hmac-SHA256", signHook(app.APISecret, body))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestComplianceHandlerCustomerRedact(t *testing.T) {
+	audit := &MemoryComplianceAuditLog{}
+	var redacted *CustomersRedact
+	h := &ComplianceHandler{
+		App:   &app,
+		Audit: audit,
+		OnCustomerRedact: func(request *CustomersRedact) error {
+			redacted = request
+			return nil
+		},
+	}
+
+	body := []byte(`{"shop_id":954889,"shop_domain":"burnsmod.myshopify.com","customer":{"id":191167,"email":"john@example.com"},"orders_to_redact":[299938,280263]}`)
+	if code := postComplianceHook(h, TopicCustomersRedact, body); code != 200 {
+		t.Fatalf("expected 200, got %d", code)
+	}
+
+	if redacted == nil || redacted.Customer.ID != 191167 || len(redacted.OrdersToRedact) != 2 {
+		t.Errorf("payload not decoded: %#v", redacted)
+	}
+
+	records := audit.Records()
+	if len(records) != 1 || records[0].Topic != TopicCustomersRedact || records[0].CompletedAt.IsZero() || records[0].Status != ComplianceCompleted {
+		t.Errorf("expected a completed audit record, got %#v", records)
+	}
+}
+
+func TestComplianceHandlerErrorsAndTimeout(t *testing.T) {
+	release := make(chan struct{})
+	audit := &MemoryComplianceAuditLog{}
+	h := &ComplianceHandler{
+		App:     &app,
+		Audit:   audit,
+		Timeout: 10 * time.Millisecond,
+		OnDataRequest: func(request *CustomersDataRequest) error {
+			return errors.New("export failed")
+		},
+		OnShopRedact: func(request *ShopRedact) error {
+			<-release
+			return nil
+		},
+	}
+
+	if code := postComplianceHook(h, TopicCustomersDataRequest, []byte(`{"shop_id":1}`)); code != 500 {
+		t.Errorf("expected 500 for a failed callback, got %d", code)
+	}
+
+	if code := postComplianceHook(h, TopicShopRedact, []byte(`{"shop_id":1}`)); code != 200 {
+		t.Errorf("expected 200 once the timeout passed, got %d", code)
+	}
+	close(release)
+
+	if code := postComplianceHook(h, "orders/create", []byte(`{}`)); code != 400 {
+		t.Errorf("expected 400 for an unsupported topic, got %d", code)
+	}
+}
+
+type failingComplianceAuditLog struct{}
+
+func (failingComplianceAuditLog) Record(record *ComplianceRecord) error {
+	return errors.New("audit log unavailable")
+}
+
+func TestComplianceHandlerUnhandledAndAuditErrors(t *testing.T) {
+	audit := &MemoryComplianceAuditLog{}
+	h := &ComplianceHandler{App: &app, Audit: audit}
+
+	if code := postComplianceHook(h, TopicShopRedact, []byte(`{"shop_id":1}`)); code != 200 {
+		t.Errorf("expected 200 for a topic without a callback, got %d", code)
+	}
+	records := audit.Records()
+	if len(records) != 1 || records[0].Status != ComplianceUnhandled || records[0].Error == "" {
+		t.Errorf("expected an unhandled audit record, got %#v", records)
+	}
+
+	var failed *ComplianceRecord
+	h = &ComplianceHandler{
+		App:          &app,
+		Audit:        failingComplianceAuditLog{},
+		AuditError:   func(record *ComplianceRecord, err error) { failed = record },
+		OnShopRedact: func(request *ShopRedact) error { return nil },
+	}
+	if code := postComplianceHook(h, TopicShopRedact, []byte(`{"shop_id":1}`)); code != 500 {
+		t.Errorf("expected 500 when the audit log fails, got %d", code)
+	}
+	if failed == nil || failed.Status != ComplianceCompleted {
+		t.Errorf("expected AuditError to get the record, got %#v", failed)
+	}
+}
@@ -12,21 +12,21 @@ import (
 )
 
 type Product struct {
-	BodyHtml       string      `json:"body_html,omitempty"`
-	CreatedAt      string      `json:"created_at,omitempty"`
-	Handle         string      `json:"handle,omitempty"`
-	ID             int64       `json:"id,omitempty"`
-	Images         interface{} `json:"images,omitempty"`
-	Options        []Option    `json:"options,omitempty"`
-	ProductType    string      `json:"product_type,omitempty"`
-	PublishedAt    string      `json:"published_at,omitempty"`
-	PublishedScope string      `json:"published_scope,omitempty"`
-	Tags           string      `json:"tags,omitempty"`
-	TemplateSuffix string      `json:"template_suffix,omitempty"`
-	Title          string      `json:"title,omitempty"`
-	UpdatedAt      string      `json:"updated_at,omitempty"`
-	Variants       []Variant   `json:"variants,omitempty"`
-	Vendor         string      `json:"vendor,omitempty"`
+	BodyHtml       string         `json:"body_html,omitempty"`
+	CreatedAt      string         `json:"created_at,omitempty"`
+	Handle         string         `json:"handle,omitempty"`
+	ID             int64          `json:"id,omitempty"`
+	Images         []ProductImage `json:"images,omitempty"`
+	Options        []Option       `json:"options,omitempty"`
+	ProductType    string         `json:"product_type,omitempty"`
+	PublishedAt    string         `json:"published_at,omitempty"`
+	PublishedScope string         `json:"published_scope,omitempty"`
+	Tags           string         `json:"tags,omitempty"`
+	TemplateSuffix string         `json:"template_suffix,omitempty"`
+	Title          string         `json:"title,omitempty"`
+	UpdatedAt      string         `json:"updated_at,omitempty"`
+	Variants       []Variant      `json:"variants,omitempty"`
+	Vendor         string         `json:"vendor,omitempty"`
 
 	api *API
 }
@@ -85,10 +85,7 @@ func (api *API) processProductsResponse(res *bytes.Buffer, status int, err error
 
 	result := (*r)["products"]
 	for _, p := range result {
-		p.api = api
-		for i := range p.Variants {
-			p.Variants[i].api = api
-		}
+		p.setAPI(api)
 	}
 
 	return result, nil
@@ -154,11 +151,22 @@ func (api *API) Product(id int64) (*Product, error) {
 		return nil, err
 	}
 
-	result.api = api
+	result.setAPI(api)
 
 	return &result, nil
 }
 
+// setAPI binds the product and its variants and images to api.
+func (obj *Product) setAPI(api *API) {
+	obj.api = api
+	for i := range obj.Variants {
+		obj.Variants[i].api = api
+	}
+	for i := range obj.Images {
+		obj.Images[i].api = api
+	}
+}
+
 func (api *API) NewProduct() *Product {
 	return &Product{api: api}
 }
@@ -313,7 +321,7 @@ func (obj *Product) Save(partial *Product) error {
 
 	api := obj.api
 	*obj = r["product"]
-	obj.api = api
+	obj.setAPI(api)
 
 	return nil
 }
@@ -0,0 +1,270 @@
+package shopify
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+type ProductImage struct {
+	ID         int64   `json:"id,omitempty"`
+	ProductID  int64   `json:"product_id,omitempty"`
+	Position   int64   `json:"position,omitempty"`
+	Src        string  `json:"src,omitempty"`
+	Attachment string  `json:"attachment,omitempty"`
+	Filename   string  `json:"filename,omitempty"`
+	Alt        string  `json:"alt,omitempty"`
+	Width      int64   `json:"width,omitempty"`
+	Height     int64   `json:"height,omitempty"`
+	VariantIDs []int64 `json:"variant_ids,omitempty"`
+	CreatedAt  string  `json:"created_at,omitempty"`
+	UpdatedAt  string  `json:"updated_at,omitempty"`
+
+	api *API
+}
+
+type ProductImageOptions struct {
+	SinceID int64  `url:"since_id,omitempty"`
+	Fields  string `url:"fields,omitempty"`
+}
+
+func (api *API) ProductImages(productID int64, options *ProductImageOptions) ([]*ProductImage, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("BASE_PATH/products/%d/images.json?%v", productID, qs)
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := &map[string][]*ProductImage{}
+	err = json.NewDecoder(res).Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := (*r)["images"]
+	for _, v := range result {
+		v.api = api
+	}
+
+	return result, nil
+}
+
+func (api *API) ProductImagesCount(productID int64) (int, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/products/%d/images/count.json", productID)
+
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if status != 200 {
+		return 0, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := map[string]interface{}{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	result, _ := strconv.Atoi(fmt.Sprintf("%v", r["count"]))
+	if err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+func (api *API) ProductImage(productID int64, id int64) (*ProductImage, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/products/%d/images/%d.json", productID, id)
+
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := map[string]ProductImage{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	result := r["image"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.api = api
+
+	return &result, nil
+}
+
+// NewProductImage starts an image for a product. Set Src to have Shopify
+// download it, or use Attach or AttachFile to upload it.
+func (api *API) NewProductImage(productID int64) *ProductImage {
+	return &ProductImage{ProductID: productID, api: api}
+}
+
+// Attach reads the image from r so Save uploads it.
+func (obj *ProductImage) Attach(r io.Reader, filename string) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	obj.Attachment = base64.StdEncoding.EncodeToString(data)
+	obj.Filename = filename
+	return nil
+}
+
+// AttachFile reads the image at path so Save uploads it.
+func (obj *ProductImage) AttachFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return obj.Attach(f, filepath.Base(path))
+}
+
+func (obj *ProductImage) Save() error {
+	endpoint := fmt.Sprintf("BASE_PATH/products/%d/images/%d.json", obj.ProductID, obj.ID)
+	method := "PUT"
+
+	if obj.ID == 0 {
+		endpoint = fmt.Sprintf("BASE_PATH/products/%d/images.json", obj.ProductID)
+		method = "POST"
+	}
+
+	body := map[string]*ProductImage{}
+	body["image"] = obj
+
+	return obj.send(endpoint, method, body)
+}
+
+// Move changes the image's position; position 1 is the product's main
+// image.
+func (obj *ProductImage) Move(position int64) error {
+	return obj.update(map[string]interface{}{"position": position})
+}
+
+// LinkVariants replaces the variants showing this image. Pass no ids to
+// unlink them all.
+func (obj *ProductImage) LinkVariants(variantIDs ...int64) error {
+	if variantIDs == nil {
+		variantIDs = []int64{}
+	}
+	return obj.update(map[string]interface{}{"variant_ids": variantIDs})
+}
+
+func (obj *ProductImage) update(fields map[string]interface{}) error {
+	endpoint := fmt.Sprintf("BASE_PATH/products/%d/images/%d.json", obj.ProductID, obj.ID)
+	fields["id"] = obj.ID
+	return obj.send(endpoint, "PUT", map[string]interface{}{"image": fields})
+}
+
+func (obj *ProductImage) send(endpoint string, method string, body interface{}) error {
+	expectedStatus := 200
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	res, status, err := obj.api.request(endpoint, method, nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return fmt.Errorf("Status %d: %v", status, r.Errors)
+		}
+
+		return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+	}
+
+	r := map[string]ProductImage{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	api := obj.api
+	*obj = r["image"]
+	obj.api = api
+
+	return nil
+}
+
+func (obj *ProductImage) Delete() error {
+	endpoint := fmt.Sprintf("BASE_PATH/products/%d/images/%d.json", obj.ProductID, obj.ID)
+	method := "DELETE"
+	expectedStatus := 200
+
+	res, status, err := obj.api.request(endpoint, method, nil, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return fmt.Errorf("Status %d: %v", status, r.Errors)
+		}
+
+		return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+	}
+
+	return nil
+}
+
+// ReorderProductImages moves the images to the order of ids, starting at
+// position 1.
+func (api *API) ReorderProductImages(productID int64, ids []int64) error {
+	for i, id := range ids {
+		image := &ProductImage{ID: id, ProductID: productID, api: api}
+		if err := image.Move(int64(i + 1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Image returns the variant's image, or nil if it has none.
+func (obj *Variant) Image() (*ProductImage, error) {
+	if obj.ImageID == 0 {
+		return nil, nil
+	}
+	return obj.api.ProductImage(obj.ProductID, obj.ImageID)
+}
+
+// ImageForVariant finds a variant's image among the product's images
+// without a request, or returns nil.
+func (obj *Product) ImageForVariant(variant *Variant) *ProductImage {
+	for i := range obj.Images {
+		if obj.Images[i].ID == variant.ImageID && variant.ImageID != 0 {
+			return &obj.Images[i]
+		}
+	}
+	return nil
+}
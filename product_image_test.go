@@ -0,0 +1,70 @@
+package shopify
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestProductImageAttachAndLink(t *testing.T) {
+	var sent []map[string]map[string]interface{}
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		sent = append(sent, body)
+
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/admin/api/2021-07/products/632910392/images.json":
+			w.Write([]byte(`{"image":{"id":850703190,"product_id":632910392,"position":2,"src":"https://cdn.shopify.com/a.png"}}`))
+		case r.Method == "PUT" && r.URL.Path == "/admin/api/2021-07/products/632910392/images/850703190.json":
+			w.Write([]byte(`{"image":{"id":850703190,"product_id":632910392,"position":1,"variant_ids":[]}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	image := newTestAPI(ts).NewProductImage(632910392)
+	if err := image.Attach(strings.NewReader("png bytes"), "a.png"); err != nil {
+		t.Fatal(err)
+	}
+	if err := image.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	attachment, _ := base64.StdEncoding.DecodeString(sent[0]["image"]["attachment"].(string))
+	if string(attachment) != "png bytes" || sent[0]["image"]["filename"] != "a.png" {
+		t.Errorf("unexpected upload %v", sent[0])
+	}
+	if image.ID != 850703190 || image.Attachment != "" {
+		t.Errorf("image not updated: %+v", image)
+	}
+
+	if err := image.Move(1); err != nil {
+		t.Fatal(err)
+	}
+	if sent[1]["image"]["position"] != float64(1) {
+		t.Errorf("unexpected move %v", sent[1])
+	}
+
+	if err := image.LinkVariants(); err != nil {
+		t.Fatal(err)
+	}
+	if ids, ok := sent[2]["image"]["variant_ids"].([]interface{}); !ok || len(ids) != 0 {
+		t.Errorf("expected an empty variant_ids list, got %v", sent[2])
+	}
+}
+
+func TestProductImageForVariant(t *testing.T) {
+	product := &Product{Images: []ProductImage{{ID: 1}, {ID: 2, Src: "b.png"}}}
+
+	if image := product.ImageForVariant(&Variant{ImageID: 2}); image == nil || image.Src != "b.png" {
+		t.Errorf("unexpected image %+v", image)
+	}
+	if image := product.ImageForVariant(&Variant{}); image != nil {
+		t.Errorf("expected no image, got %+v", image)
+	}
+}
@@ -4,30 +4,42 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/url"
 )
 
 const (
-	StatusAccepted = "accepted"
-	StatusDeclined = "declined"
+	StatusPending   = "pending"
+	StatusAccepted  = "accepted"
+	StatusActive    = "active"
+	StatusDeclined  = "declined"
+	StatusExpired   = "expired"
+	StatusFrozen    = "frozen"
+	StatusCancelled = "cancelled"
 )
 
 type RecurringApplicationCharge struct {
-	ActivatedOn        string `json:"activated_on,omitempty"`
-	APIClientID        int64  `json:"api_client_id,omitempty"`
-	BillingOn          string `json:"billing_on,omitempty"`
-	CancelledOn        string `json:"cancelled_on,omitempty"`
-	ConfirmationURL    string `json:"confirmation_url,omitempty"`
-	DecoratedReturnURL string `json:"decorated_return_url,omitempty"`
-	CreatedAt          string `json:"created_at,omitempty"`
-	ID                 int64  `json:"id,omitempty"`
-	Name               string `json:"name,omitempty"`
-	Status             string `json:"status,omitempty"`
-	Price              string `json:"price,omitempty"`
-	ReturnURL          string `json:"return_url,omitempty"`
-	Test               bool   `json:"test,omitempty"`
-	TrialDays          int    `json:"trial_days,omitempty"`
-	TrialEndsOn        string `json:"trial_ends_on,omitempty"`
-	UpdatedAt          string `json:"updated_at,omitempty"`
+	ActivatedOn           string  `json:"activated_on,omitempty"`
+	APIClientID           int64   `json:"api_client_id,omitempty"`
+	BalanceRemaining      float64 `json:"balance_remaining,omitempty"`
+	BalanceUsed           float64 `json:"balance_used,omitempty"`
+	BillingOn             string  `json:"billing_on,omitempty"`
+	CancelledOn           string  `json:"cancelled_on,omitempty"`
+	CappedAmount          string  `json:"capped_amount,omitempty"`
+	ConfirmationURL       string  `json:"confirmation_url,omitempty"`
+	DecoratedReturnURL    string  `json:"decorated_return_url,omitempty"`
+	CreatedAt             string  `json:"created_at,omitempty"`
+	ID                    int64   `json:"id,omitempty"`
+	Name                  string  `json:"name,omitempty"`
+	Status                string  `json:"status,omitempty"`
+	Price                 string  `json:"price,omitempty"`
+	ReturnURL             string  `json:"return_url,omitempty"`
+	Terms                 string  `json:"terms,omitempty"`
+	Test                  bool    `json:"test,omitempty"`
+	TrialDays             int     `json:"trial_days,omitempty"`
+	TrialEndsOn           string  `json:"trial_ends_on,omitempty"`
+	UpdateCappedAmountURL string  `json:"update_capped_amount_url,omitempty"`
+	UpdatedAt             string  `json:"updated_at,omitempty"`
 
 	api *API
 }
@@ -39,10 +51,16 @@ type RecurringApplicationChargeOptions struct {
 
 // RecurringApplicationCharges Retrieve all recurring application charges
 func (api *API) RecurringApplicationCharges(options *RecurringApplicationChargeOptions) ([]*RecurringApplicationCharge, error) {
+	return api.recurringApplicationCharges(options, nil)
+}
+
+// recurringApplicationCharges lists the charges; pass noCache as params
+// when the shop's current subscription matters.
+func (api *API) recurringApplicationCharges(options *RecurringApplicationChargeOptions, params map[string]interface{}) ([]*RecurringApplicationCharge, error) {
 
 	qs := encodeOptions(options)
-	endpoint := fmt.Sprintf("/admin/recurring_application_charges.json?%v", qs)
-	res, status, err := api.request(endpoint, "GET", nil, nil)
+	endpoint := fmt.Sprintf("BASE_PATH/recurring_application_charges.json?%v", qs)
+	res, status, err := api.request(endpoint, "GET", params, nil)
 
 	if err != nil {
 		return nil, err
@@ -67,9 +85,15 @@ func (api *API) RecurringApplicationCharges(options *RecurringApplicationChargeO
 }
 
 func (api *API) RecurringApplicationCharge(id int64) (*RecurringApplicationCharge, error) {
-	endpoint := fmt.Sprintf("/admin/recurring_application_charges/%d.json", id)
+	return api.recurringApplicationCharge(id, nil)
+}
 
-	res, status, err := api.request(endpoint, "GET", nil, nil)
+// recurringApplicationCharge fetches the charge; pass noCache as params
+// when the current balance or status matters.
+func (api *API) recurringApplicationCharge(id int64, params map[string]interface{}) (*RecurringApplicationCharge, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/recurring_application_charges/%d.json", id)
+
+	res, status, err := api.request(endpoint, "GET", params, nil)
 
 	if err != nil {
 		return nil, err
@@ -99,7 +123,7 @@ func (api *API) NewRecurringApplicationCharge() *RecurringApplicationCharge {
 
 func (obj *RecurringApplicationCharge) Save() error {
 
-	endpoint := fmt.Sprintf("/admin/recurring_application_charges.json")
+	endpoint := fmt.Sprintf("BASE_PATH/recurring_application_charges.json")
 	method := "POST"
 	expectedStatus := 201
 
@@ -144,11 +168,11 @@ func (obj *RecurringApplicationCharge) Save() error {
 }
 
 func (obj *RecurringApplicationCharge) Activate() error {
-	endpoint := fmt.Sprintf("/admin/recurring_application_charges/%d/activate.json", obj.ID)
+	endpoint := fmt.Sprintf("BASE_PATH/recurring_application_charges/%d/activate.json", obj.ID)
 	method := "POST"
 	expectedStatus := 200
 
-	res, status, err := obj.api.request(endpoint, method, nil, nil)
+	res, status, err := obj.api.request(endpoint, method, noCache, nil)
 
 	if err != nil {
 		return err
@@ -164,11 +188,24 @@ func (obj *RecurringApplicationCharge) Activate() error {
 		}
 	}
 
+	r := map[string]RecurringApplicationCharge{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	if activated, ok := r["recurring_application_charge"]; ok {
+		api := obj.api
+		*obj = activated
+		obj.api = api
+	}
+
 	return nil
 }
 
 func (obj *RecurringApplicationCharge) Delete() error {
-	endpoint := fmt.Sprintf("/admin/recurring_application_charges/%d.json", obj.ID)
+	endpoint := fmt.Sprintf("BASE_PATH/recurring_application_charges/%d.json", obj.ID)
 	method := "DELETE"
 	expectedStatus := 200
 
@@ -190,3 +227,42 @@ func (obj *RecurringApplicationCharge) Delete() error {
 
 	return nil
 }
+
+// Customize raises the charge's capped amount. The merchant has to approve
+// the change at the returned URL before it takes effect.
+func (obj *RecurringApplicationCharge) Customize(cappedAmount string) (string, error) {
+	params := url.Values{}
+	params.Set("recurring_application_charge[capped_amount]", cappedAmount)
+	endpoint := fmt.Sprintf("BASE_PATH/recurring_application_charges/%d/customize.json?%s", obj.ID, params.Encode())
+	method := "PUT"
+	expectedStatus := 200
+
+	res, status, err := obj.api.request(endpoint, method, noCache, nil)
+
+	if err != nil {
+		return "", err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return "", fmt.Errorf("Status %d: %v", status, r.Errors)
+		} else {
+			return "", fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+		}
+	}
+
+	r := map[string]RecurringApplicationCharge{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return "", err
+	}
+
+	api := obj.api
+	*obj = r["recurring_application_charge"]
+	obj.api = api
+
+	return obj.UpdateCappedAmountURL, nil
+}
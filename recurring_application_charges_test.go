@@ -0,0 +1,32 @@
+package shopify
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecurringApplicationChargeActivateIsNotCached(t *testing.T) {
+	activations := 0
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/admin/api/2021-07/recurring_application_charges/455696195/activate.json" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		activations++
+		w.Write([]byte(`{"recurring_application_charge":{"id":455696195,"status":"active"}}`))
+	}))
+	defer ts.Close()
+
+	api := newTestAPI(ts)
+	api.RequestCache = memoryRequestCache{}
+	charge := &RecurringApplicationCharge{ID: 455696195, api: api}
+
+	for i := 0; i < 2; i++ {
+		if err := charge.Activate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if activations != 2 || charge.Status != StatusActive {
+		t.Errorf("expected both activations to reach Shopify, got %d", activations)
+	}
+}
@@ -0,0 +1,199 @@
+package shopify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	RestockTypeNoRestock     = "no_restock"
+	RestockTypeCancel        = "cancel"
+	RestockTypeReturn        = "return"
+	RestockTypeLegacyRestock = "legacy_restock"
+)
+
+type RefundLineItem struct {
+	ID          int64     `json:"id,omitempty"`
+	LineItemID  int64     `json:"line_item_id,omitempty"`
+	LineItem    *LineItem `json:"line_item,omitempty"`
+	LocationID  int64     `json:"location_id,omitempty"`
+	Quantity    int64     `json:"quantity,omitempty"`
+	RestockType string    `json:"restock_type,omitempty"`
+	Subtotal    float64   `json:"subtotal,omitempty"`
+	TotalTax    float64   `json:"total_tax,omitempty"`
+}
+
+type OrderAdjustment struct {
+	Amount    string `json:"amount,omitempty"`
+	ID        int64  `json:"id,omitempty"`
+	Kind      string `json:"kind,omitempty"`
+	OrderID   int64  `json:"order_id,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	RefundID  int64  `json:"refund_id,omitempty"`
+	TaxAmount string `json:"tax_amount,omitempty"`
+}
+
+// RefundShipping refunds shipping, either all of it or Amount.
+type RefundShipping struct {
+	FullRefund        bool   `json:"full_refund,omitempty"`
+	Amount            string `json:"amount,omitempty"`
+	Tax               string `json:"tax,omitempty"`
+	MaximumRefundable string `json:"maximum_refundable,omitempty"`
+}
+
+type Refund struct {
+	CreatedAt        string            `json:"created_at,omitempty"`
+	Currency         string            `json:"currency,omitempty"`
+	ID               int64             `json:"id,omitempty"`
+	Note             string            `json:"note,omitempty"`
+	OrderAdjustments []OrderAdjustment `json:"order_adjustments,omitempty"`
+	OrderID          int64             `json:"order_id,omitempty"`
+	ProcessedAt      string            `json:"processed_at,omitempty"`
+	RefundLineItems  []RefundLineItem  `json:"refund_line_items,omitempty"`
+	Restock          bool              `json:"restock,omitempty"`
+	Shipping         *RefundShipping   `json:"shipping,omitempty"`
+	Transactions     []Transaction     `json:"transactions,omitempty"`
+	UserID           int64             `json:"user_id,omitempty"`
+
+	api *API
+}
+
+// RefundRequest is sent to calculate or create a refund.
+type RefundRequest struct {
+	Currency          string           `json:"currency,omitempty"`
+	DiscrepancyReason string           `json:"discrepancy_reason,omitempty"`
+	Note              string           `json:"note,omitempty"`
+	Notify            bool             `json:"notify,omitempty"`
+	RefundLineItems   []RefundLineItem `json:"refund_line_items,omitempty"`
+	Shipping          *RefundShipping  `json:"shipping,omitempty"`
+	Transactions      []Transaction    `json:"transactions,omitempty"`
+}
+
+func (api *API) OrderRefunds(orderID int64) ([]*Refund, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/orders/%d/refunds.json", orderID)
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := &map[string][]*Refund{}
+	err = json.NewDecoder(res).Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := (*r)["refunds"]
+	for _, v := range result {
+		v.api = api
+	}
+
+	return result, nil
+}
+
+func (api *API) Refund(orderID int64, id int64) (*Refund, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/orders/%d/refunds/%d.json", orderID, id)
+
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := map[string]Refund{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	result := r["refund"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.api = api
+
+	return &result, nil
+}
+
+// CalculateRefund asks Shopify what a refund would look like. The result
+// carries suggested_refund transactions; see RefundTransactions.
+func (api *API) CalculateRefund(orderID int64, request *RefundRequest) (*Refund, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/orders/%d/refunds/calculate.json", orderID)
+	return api.postRefund(endpoint, 200, request)
+}
+
+func (api *API) CreateRefund(orderID int64, request *RefundRequest) (*Refund, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/orders/%d/refunds.json", orderID)
+	return api.postRefund(endpoint, 201, request)
+}
+
+func (obj *Order) CalculateRefund(request *RefundRequest) (*Refund, error) {
+	return obj.api.CalculateRefund(obj.Id, request)
+}
+
+func (obj *Order) CreateRefund(request *RefundRequest) (*Refund, error) {
+	return obj.api.CreateRefund(obj.Id, request)
+}
+
+// RefundTransactions turns a calculated refund's suggested transactions
+// into the refund transactions to send with CreateRefund.
+func (obj *Refund) RefundTransactions() []Transaction {
+	result := []Transaction{}
+	for _, suggested := range obj.Transactions {
+		result = append(result, Transaction{
+			Amount:   suggested.Amount,
+			Gateway:  suggested.Gateway,
+			Kind:     TransactionKindRefund,
+			ParentID: suggested.ParentID,
+		})
+	}
+	return result
+}
+
+func (api *API) postRefund(endpoint string, expectedStatus int, request *RefundRequest) (*Refund, error) {
+	body := map[string]*RefundRequest{}
+	body["refund"] = request
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, status, err := api.request(endpoint, "POST", nil, buf)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return nil, fmt.Errorf("Status %d: %v", status, r.Errors)
+		}
+
+		return nil, fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+	}
+
+	r := map[string]Refund{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := r["refund"]
+	result.api = api
+
+	return &result, nil
+}
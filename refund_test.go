@@ -0,0 +1,43 @@
+package shopify
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRefundDecodesFromOrder(t *testing.T) {
+	body := `{"id":450789469,"refunds":[{"id":509562969,"order_id":450789469,"note":"it broke during shipping",
+		"refund_line_items":[{"id":104689539,"quantity":1,"line_item_id":703073504,"location_id":487838322,"restock_type":"return","subtotal":195.66,"total_tax":3.98,
+			"line_item":{"id":703073504,"title":"IPod Nano - 8gb"}}],
+		"transactions":[{"id":179259969,"kind":"refund","gateway":"bogus","amount":"209.00","parent_id":389404469}],
+		"order_adjustments":[{"id":1,"kind":"shipping_refund","amount":"-5.00","tax_amount":"0.00"}]}]}`
+
+	order := Order{}
+	if err := json.Unmarshal([]byte(body), &order); err != nil {
+		t.Fatal(err)
+	}
+
+	refund := order.Refunds[0]
+	item := refund.RefundLineItems[0]
+	if item.RestockType != RestockTypeReturn || item.LocationID != 487838322 || item.LineItem.Title != "IPod Nano - 8gb" || item.Subtotal != 195.66 {
+		t.Errorf("refund line item not decoded: %#v", item)
+	}
+	if refund.OrderAdjustments[0].Kind != "shipping_refund" {
+		t.Errorf("order adjustment not decoded: %#v", refund.OrderAdjustments)
+	}
+}
+
+func TestRefundTransactions(t *testing.T) {
+	calculated := &Refund{Transactions: []Transaction{
+		{Kind: "suggested_refund", Gateway: "bogus", Amount: "41.94", ParentID: 801038806, MaximumRefundable: "41.94"},
+	}}
+
+	transactions := calculated.RefundTransactions()
+	if len(transactions) != 1 {
+		t.Fatalf("expected 1 transaction, got %d", len(transactions))
+	}
+	tx := transactions[0]
+	if tx.Kind != "refund" || tx.ParentID != 801038806 || tx.Amount != "41.94" || tx.MaximumRefundable != "" {
+		t.Errorf("unexpected refund transaction: %#v", tx)
+	}
+}
@@ -0,0 +1,172 @@
+package shopify
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const TopicAppSubscriptionsUpdate = "app_subscriptions/update"
+
+const DEFAULT_SUBSCRIPTION_CACHE_TTL = 10 * time.Minute
+
+// AppSubscription is the subscription in an app_subscriptions/update
+// payload. IDs are GraphQL global IDs.
+type AppSubscription struct {
+	AdminGraphqlAPIID     string `json:"admin_graphql_api_id,omitempty"`
+	AdminGraphqlAPIShopID string `json:"admin_graphql_api_shop_id,omitempty"`
+	Name                  string `json:"name,omitempty"`
+	Status                string `json:"status,omitempty"`
+	CappedAmount          string `json:"capped_amount,omitempty"`
+	CreatedAt             string `json:"created_at,omitempty"`
+	UpdatedAt             string `json:"updated_at,omitempty"`
+}
+
+// AppSubscriptionsUpdate is the payload of app_subscriptions/update.
+type AppSubscriptionsUpdate struct {
+	AppSubscription AppSubscription `json:"app_subscription"`
+}
+
+type subscriptionContextKey struct{}
+
+// EntitlementFromContext returns the entitlement SubscriptionGate stored
+// for the request.
+func EntitlementFromContext(ctx context.Context) (string, bool) {
+	entitlement, ok := ctx.Value(subscriptionContextKey{}).(string)
+	return entitlement, ok
+}
+
+type subscriptionCacheEntry struct {
+	charge  *RecurringApplicationCharge
+	expires time.Time
+}
+
+// SubscriptionGate only lets shops with an active recurring charge through.
+// Active charges are cached per shop; pass app_subscriptions/update webhooks
+// to HandleWebhook so changes show up straight away.
+type SubscriptionGate struct {
+	// App verifies the signature on the shop query parameter when
+	// ShopFromRequest isn't set.
+	App *App
+
+	APIForShop func(shop string) (*API, error)
+
+	// BillingURL is where shops without an active charge are redirected,
+	// with the shop parameter added.
+	BillingURL string
+
+	// Entitlements maps plan names to the entitlement put on the request
+	// context. Plans not listed use their name.
+	Entitlements map[string]string
+
+	// ShopFromRequest finds the shop for a request, e.g. from the app's
+	// session, and must only return shops the app has authenticated.
+	// Defaults to the shop query parameter, which is only used when the
+	// request's HMAC signature verifies against App.
+	ShopFromRequest func(r *http.Request) string
+
+	// CacheTTL is how long an active charge is cached for.
+	CacheTTL time.Duration
+
+	// NegativeCacheTTL is how long to remember that a shop has no active
+	// charge. Zero doesn't cache it, so a shop that has just subscribed is
+	// let through straight away; keep it short if set.
+	NegativeCacheTTL time.Duration
+
+	mu    sync.Mutex
+	cache map[string]subscriptionCacheEntry
+}
+
+// ActiveCharge returns the shop's active charge, or nil if it has none,
+// using the cache when it is fresh.
+func (g *SubscriptionGate) ActiveCharge(shop string) (*RecurringApplicationCharge, error) {
+	g.mu.Lock()
+	entry, ok := g.cache[shop]
+	g.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.charge, nil
+	}
+	return g.Refresh(shop)
+}
+
+// Refresh looks up the shop's active charge and replaces the cached value.
+func (g *SubscriptionGate) Refresh(shop string) (*RecurringApplicationCharge, error) {
+	api, err := g.APIForShop(shop)
+	if err != nil {
+		return nil, err
+	}
+
+	charge, err := activeCharge(api)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := g.CacheTTL
+	if ttl == 0 {
+		ttl = DEFAULT_SUBSCRIPTION_CACHE_TTL
+	}
+	if charge == nil {
+		ttl = g.NegativeCacheTTL
+	}
+
+	g.mu.Lock()
+	if g.cache == nil {
+		g.cache = map[string]subscriptionCacheEntry{}
+	}
+	if ttl > 0 {
+		g.cache[shop] = subscriptionCacheEntry{charge: charge, expires: time.Now().Add(ttl)}
+	} else {
+		delete(g.cache, shop)
+	}
+	g.mu.Unlock()
+
+	return charge, nil
+}
+
+// Invalidate drops the cached charge for shop.
+func (g *SubscriptionGate) Invalidate(shop string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.cache, shop)
+}
+
+// HandleWebhook refreshes the cache for app_subscriptions/update events.
+// It can be used as, or called from, a WebhookHandler's Handle func.
+func (g *SubscriptionGate) HandleWebhook(event *WebhookEvent) error {
+	if event.Topic != TopicAppSubscriptionsUpdate {
+		return nil
+	}
+	g.Invalidate(event.ShopDomain)
+	_, err := g.Refresh(event.ShopDomain)
+	return err
+}
+
+func (g *SubscriptionGate) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		shop, status, err := requestShop(g.App, r, g.ShopFromRequest)
+		if err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		charge, err := g.ActiveCharge(shop)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		if charge == nil {
+			http.Redirect(w, r, withShop(g.BillingURL, shop), http.StatusFound)
+			return
+		}
+
+		entitlement := charge.Name
+		if mapped, ok := g.Entitlements[charge.Name]; ok {
+			entitlement = mapped
+		}
+
+		ctx := context.WithValue(r.Context(), subscriptionContextKey{}, entitlement)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
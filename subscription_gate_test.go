@@ -0,0 +1,116 @@
+package shopify
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSubscriptionGate(t *testing.T) {
+	standIn := NewBillingStandIn()
+	defer standIn.Close()
+	api := standIn.API()
+	// charge lookups must see changes made since the last request
+	api.RequestCache = memoryRequestCache{}
+
+	gate := &SubscriptionGate{
+		App: &app,
+		APIForShop: func(shop string) (*API, error) {
+			return api, nil
+		},
+		BillingURL:   "/billing",
+		Entitlements: map[string]string{"Pro": "premium"},
+	}
+
+	var entitlement string
+	handler := gate.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entitlement, _ = EntitlementFromContext(r.Context())
+	}))
+
+	serve := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/reports?"+signQuery(app.APISecret, "shop=burnsmod.myshopify.com"), nil))
+		return w
+	}
+
+	if w := serve(); w.Code != http.StatusFound || w.Header().Get("Location") != "/billing?shop=burnsmod.myshopify.com" {
+		t.Fatalf("expected redirect to billing, got %d %s", w.Code, w.Header().Get("Location"))
+	}
+
+	// activate a charge behind the gate's back
+	charge := api.NewRecurringApplicationCharge()
+	charge.Name = "Pro"
+	charge.Price = "30.00"
+	charge.ReturnURL = "https://app.com/billing"
+	if err := charge.Save(); err != nil {
+		t.Fatal(err)
+	}
+	client := standIn.Server.Client()
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	resp, err := client.Get(charge.ConfirmationURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if err := charge.Activate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// not having a charge isn't cached, so the shop is let in straight away
+	if w := serve(); w.Code != http.StatusOK {
+		t.Fatalf("expected the request through once subscribed, got %d", w.Code)
+	}
+	if entitlement != "premium" {
+		t.Errorf("expected premium entitlement, got %q", entitlement)
+	}
+
+	// active charges are cached until the webhook says otherwise
+	charge.Delete()
+	if w := serve(); w.Code != http.StatusOK {
+		t.Errorf("expected the cached charge to be used, got %d", w.Code)
+	}
+	event := &WebhookEvent{Topic: TopicAppSubscriptionsUpdate, ShopDomain: "burnsmod.myshopify.com"}
+	if err := gate.HandleWebhook(event); err != nil {
+		t.Fatal(err)
+	}
+	if w := serve(); w.Code != http.StatusFound {
+		t.Errorf("expected redirect to billing after the webhook, got %d", w.Code)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/reports?shop=burnsmod.myshopify.com", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected an unsigned request to be refused, got %d", w.Code)
+	}
+}
+
+func TestSubscriptionGateNegativeCacheTTL(t *testing.T) {
+	lookups := 0
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups++
+		w.Write([]byte(`{"recurring_application_charges":[]}`))
+	}))
+	defer ts.Close()
+
+	gate := &SubscriptionGate{
+		APIForShop: func(shop string) (*API, error) {
+			return newTestAPI(ts), nil
+		},
+	}
+
+	gate.ActiveCharge("burnsmod.myshopify.com")
+	gate.ActiveCharge("burnsmod.myshopify.com")
+	if lookups != 2 {
+		t.Errorf("expected no charge not to be cached, got %d lookups", lookups)
+	}
+
+	gate.NegativeCacheTTL = time.Minute
+	gate.ActiveCharge("burnsmod.myshopify.com")
+	gate.ActiveCharge("burnsmod.myshopify.com")
+	if lookups != 3 {
+		t.Errorf("expected no charge to be cached for NegativeCacheTTL, got %d lookups", lookups)
+	}
+}
@@ -0,0 +1,295 @@
+package shopify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+type TransactionKind string
+
+const (
+	TransactionKindAuthorization   TransactionKind = "authorization"
+	TransactionKindCapture         TransactionKind = "capture"
+	TransactionKindSale            TransactionKind = "sale"
+	TransactionKindVoid            TransactionKind = "void"
+	TransactionKindRefund          TransactionKind = "refund"
+	TransactionKindSuggestedRefund TransactionKind = "suggested_refund"
+)
+
+type TransactionStatus string
+
+const (
+	TransactionStatusPending TransactionStatus = "pending"
+	TransactionStatusFailure TransactionStatus = "failure"
+	TransactionStatusSuccess TransactionStatus = "success"
+	TransactionStatusError   TransactionStatus = "error"
+)
+
+type TransactionGateway string
+
+const (
+	TransactionGatewayBogus           TransactionGateway = "bogus"
+	TransactionGatewayManual          TransactionGateway = "manual"
+	TransactionGatewayShopifyPayments TransactionGateway = "shopify_payments"
+	TransactionGatewayGiftCard        TransactionGateway = "gift_card"
+)
+
+type Transaction struct {
+	Amount            string             `json:"amount,omitempty"`
+	Authorization     string             `json:"authorization,omitempty"`
+	CreatedAt         string             `json:"created_at,omitempty"`
+	Currency          string             `json:"currency,omitempty"`
+	DeviceID          int64              `json:"device_id,omitempty"`
+	ErrorCode         string             `json:"error_code,omitempty"`
+	Gateway           TransactionGateway `json:"gateway,omitempty"`
+	ID                int64              `json:"id,omitempty"`
+	Kind              TransactionKind    `json:"kind,omitempty"`
+	LocationID        int64              `json:"location_id,omitempty"`
+	MaximumRefundable string             `json:"maximum_refundable,omitempty"`
+	Message           string             `json:"message,omitempty"`
+	OrderID           int64              `json:"order_id,omitempty"`
+	ParentID          int64              `json:"parent_id,omitempty"`
+	ProcessedAt       string             `json:"processed_at,omitempty"`
+	SourceName        string             `json:"source_name,omitempty"`
+	Status            TransactionStatus  `json:"status,omitempty"`
+	Test              bool               `json:"test,omitempty"`
+	UserID            int64              `json:"user_id,omitempty"`
+
+	api *API
+}
+
+type TransactionOptions struct {
+	SinceID int64  `url:"since_id,omitempty"`
+	Fields  string `url:"fields,omitempty"`
+}
+
+func (api *API) OrderTransactions(orderID int64, options *TransactionOptions) ([]*Transaction, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("BASE_PATH/orders/%d/transactions.json?%v", orderID, qs)
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := &map[string][]*Transaction{}
+	err = json.NewDecoder(res).Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := (*r)["transactions"]
+	for _, v := range result {
+		v.api = api
+	}
+
+	return result, nil
+}
+
+func (api *API) OrderTransactionsCount(orderID int64) (int, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/orders/%d/transactions/count.json", orderID)
+
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if status != 200 {
+		return 0, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := map[string]interface{}{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	result, _ := strconv.Atoi(fmt.Sprintf("%v", r["count"]))
+	if err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+func (api *API) OrderTransaction(orderID int64, id int64) (*Transaction, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/orders/%d/transactions/%d.json", orderID, id)
+
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := map[string]Transaction{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	result := r["transaction"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.api = api
+
+	return &result, nil
+}
+
+func (api *API) NewTransaction(orderID int64) *Transaction {
+	return &Transaction{OrderID: orderID, api: api}
+}
+
+// Save creates the transaction on its order. Transactions can't be changed
+// once created.
+func (obj *Transaction) Save() error {
+	endpoint := fmt.Sprintf("BASE_PATH/orders/%d/transactions.json", obj.OrderID)
+	method := "POST"
+	expectedStatus := 201
+
+	body := map[string]*Transaction{}
+	body["transaction"] = obj
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	res, status, err := obj.api.request(endpoint, method, nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return fmt.Errorf("Status %d: %v", status, r.Errors)
+		}
+
+		return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+	}
+
+	r := map[string]Transaction{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	api := obj.api
+	*obj = r["transaction"]
+	obj.api = api
+
+	return nil
+}
+
+func (obj *Order) Transactions() ([]*Transaction, error) {
+	return obj.api.OrderTransactions(obj.Id, nil)
+}
+
+// Capture takes payment for an authorization. parentID may be 0 when the
+// order has a single authorization; an empty amount captures all of it.
+func (obj *Order) Capture(parentID int64, amount string, currency string) (*Transaction, error) {
+	return obj.createTransaction(TransactionKindCapture, parentID, amount, currency)
+}
+
+// Void cancels an authorization.
+func (obj *Order) Void(parentID int64) (*Transaction, error) {
+	return obj.createTransaction(TransactionKindVoid, parentID, "", "")
+}
+
+// RefundTransaction returns money from a capture or sale. Use CreateRefund
+// to also refund line items or restock.
+func (obj *Order) RefundTransaction(parentID int64, amount string, currency string) (*Transaction, error) {
+	return obj.createTransaction(TransactionKindRefund, parentID, amount, currency)
+}
+
+func (obj *Order) createTransaction(kind TransactionKind, parentID int64, amount string, currency string) (*Transaction, error) {
+	transaction := obj.api.NewTransaction(obj.Id)
+	transaction.Kind = kind
+	transaction.ParentID = parentID
+	transaction.Amount = amount
+	transaction.Currency = currency
+
+	if err := transaction.Save(); err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// CapturableBalance fetches the order's transactions and returns the
+// authorized amount that hasn't been captured or voided yet.
+func (obj *Order) CapturableBalance() (string, error) {
+	transactions, err := obj.Transactions()
+	if err != nil {
+		return "", err
+	}
+	return CapturableBalance(transactions)
+}
+
+// CapturableBalance returns the authorized amount in transactions that
+// hasn't been captured or voided yet, in the precision of the
+// transactions' currency.
+func CapturableBalance(transactions []*Transaction) (string, error) {
+	decimals := 2
+	if len(transactions) > 0 {
+		decimals = CurrencyDecimals(transactions[0].Currency)
+	}
+
+	authorized := map[int64]int64{}
+	for _, t := range transactions {
+		if t.Kind == TransactionKindAuthorization && t.Status == TransactionStatusSuccess {
+			amount, err := parseMinorUnits(t.Amount, decimals)
+			if err != nil {
+				return "", err
+			}
+			authorized[t.ID] = amount
+		}
+	}
+
+	var unmatched int64
+	for _, t := range transactions {
+		if t.Status != TransactionStatusSuccess {
+			continue
+		}
+
+		switch t.Kind {
+		case TransactionKindCapture:
+			amount, err := parseMinorUnits(t.Amount, decimals)
+			if err != nil {
+				return "", err
+			}
+			if _, ok := authorized[t.ParentID]; ok {
+				authorized[t.ParentID] -= amount
+			} else {
+				unmatched += amount
+			}
+		case TransactionKindVoid:
+			if _, ok := authorized[t.ParentID]; ok {
+				authorized[t.ParentID] = 0
+			}
+		}
+	}
+
+	balance := -unmatched
+	for _, remaining := range authorized {
+		if remaining > 0 {
+			balance += remaining
+		}
+	}
+	if balance < 0 {
+		balance = 0
+	}
+
+	return formatMinorUnits(balance, decimals), nil
+}
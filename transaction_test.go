@@ -0,0 +1,32 @@
+package shopify
+
+import (
+	"testing"
+)
+
+func TestCapturableBalance(t *testing.T) {
+	transactions := []*Transaction{
+		{ID: 1, Kind: TransactionKindAuthorization, Status: TransactionStatusSuccess, Amount: "100.00"},
+		{ID: 2, Kind: TransactionKindCapture, Status: TransactionStatusSuccess, Amount: "30.50", ParentID: 1},
+		{ID: 3, Kind: TransactionKindCapture, Status: TransactionStatusFailure, Amount: "50.00", ParentID: 1},
+		{ID: 4, Kind: TransactionKindAuthorization, Status: TransactionStatusSuccess, Amount: "20.00"},
+		{ID: 5, Kind: TransactionKindVoid, Status: TransactionStatusSuccess, ParentID: 4},
+		{ID: 6, Kind: TransactionKindAuthorization, Status: TransactionStatusFailure, Amount: "999.00"},
+	}
+
+	balance, err := CapturableBalance(transactions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance != "69.50" {
+		t.Errorf("expected 69.50, got %s", balance)
+	}
+
+	balance, err = CapturableBalance([]*Transaction{
+		{ID: 1, Kind: TransactionKindAuthorization, Status: TransactionStatusSuccess, Amount: "10.125", Currency: "KWD"},
+		{ID: 2, Kind: TransactionKindCapture, Status: TransactionStatusSuccess, Amount: "2.005", Currency: "KWD", ParentID: 1},
+	})
+	if err != nil || balance != "8.120" {
+		t.Errorf("expected 8.120, got %s, %v", balance, err)
+	}
+}
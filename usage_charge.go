@@ -0,0 +1,176 @@
+package shopify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrUsageChargeExceedsBalance is returned when a usage charge would take
+// the recurring charge past its capped amount.
+var ErrUsageChargeExceedsBalance = errors.New("usage charge exceeds the remaining balance")
+
+type UsageCharge struct {
+	BalanceRemaining             float64 `json:"balance_remaining,omitempty"`
+	BalanceUsed                  float64 `json:"balance_used,omitempty"`
+	BillingOn                    string  `json:"billing_on,omitempty"`
+	CreatedAt                    string  `json:"created_at,omitempty"`
+	Description                  string  `json:"description,omitempty"`
+	ID                           int64   `json:"id,omitempty"`
+	Price                        string  `json:"price,omitempty"`
+	RecurringApplicationChargeID int64   `json:"recurring_application_charge_id,omitempty"`
+	RiskLevel                    float64 `json:"risk_level,omitempty"`
+	UpdatedAt                    string  `json:"updated_at,omitempty"`
+
+	api *API
+}
+
+// UsageCharges Retrieve all usage charges under a recurring application charge
+func (api *API) UsageCharges(chargeID int64) ([]*UsageCharge, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/recurring_application_charges/%d/usage_charges.json", chargeID)
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := &map[string][]*UsageCharge{}
+	err = json.NewDecoder(res).Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := (*r)["usage_charges"]
+	for _, v := range result {
+		v.api = api
+	}
+
+	return result, nil
+}
+
+func (api *API) UsageCharge(chargeID int64, id int64) (*UsageCharge, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/recurring_application_charges/%d/usage_charges/%d.json", chargeID, id)
+
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := map[string]UsageCharge{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	result := r["usage_charge"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.api = api
+
+	return &result, nil
+}
+
+func (api *API) NewUsageCharge(chargeID int64) *UsageCharge {
+	return &UsageCharge{RecurringApplicationChargeID: chargeID, api: api}
+}
+
+func (obj *UsageCharge) Save() error {
+	endpoint := fmt.Sprintf("BASE_PATH/recurring_application_charges/%d/usage_charges.json", obj.RecurringApplicationChargeID)
+	method := "POST"
+	expectedStatus := 201
+
+	body := map[string]*UsageCharge{}
+	body["usage_charge"] = obj
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	res, status, err := obj.api.request(endpoint, method, nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return fmt.Errorf("Status %d: %v", status, r.Errors)
+		} else {
+			return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+		}
+	}
+
+	r := map[string]UsageCharge{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	api := obj.api
+	*obj = r["usage_charge"]
+	obj.api = api
+
+	return nil
+}
+
+// CreateUsageCharge bills price against the charge's capped amount. It
+// reloads the charge first, and refuses to bill a charge that isn't active
+// or to create one the remaining balance can't cover, returning
+// ErrUsageChargeExceedsBalance for the latter. App charges are billed in
+// USD.
+func (obj *RecurringApplicationCharge) CreateUsageCharge(description string, price string) (*UsageCharge, error) {
+	current, err := obj.api.recurringApplicationCharge(obj.ID, noCache)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status != StatusActive {
+		return nil, fmt.Errorf("Charge %d is %s, not active", obj.ID, current.Status)
+	}
+
+	capped, err := parseCents(current.CappedAmount)
+	if err != nil {
+		return nil, err
+	}
+	if capped == 0 {
+		return nil, fmt.Errorf("Charge %d has no capped amount", obj.ID)
+	}
+
+	cost, err := parseCents(price)
+	if err != nil {
+		return nil, err
+	}
+
+	used := int64(math.Round(current.BalanceUsed * 100))
+	if used+cost > capped {
+		return nil, ErrUsageChargeExceedsBalance
+	}
+
+	usage := obj.api.NewUsageCharge(obj.ID)
+	usage.Description = description
+	usage.Price = price
+	if err := usage.Save(); err != nil {
+		return nil, err
+	}
+
+	obj.BalanceUsed = usage.BalanceUsed
+	obj.BalanceRemaining = usage.BalanceRemaining
+
+	return usage, nil
+}
@@ -0,0 +1,84 @@
+package shopify
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateUsageChargeRefusesOverCap(t *testing.T) {
+	created := 0
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/admin/api/2021-07/recurring_application_charges/455696195.json":
+			w.Write([]byte(`{"recurring_application_charge":{"id":455696195,"status":"active","capped_amount":"100.00","balance_used":95.5}}`))
+		case "/admin/api/2021-07/recurring_application_charges/455696195/usage_charges.json":
+			created++
+			w.WriteHeader(201)
+			w.Write([]byte(`{"usage_charge":{"id":1034618207,"description":"Super Mega Plan 1000 emails","price":"4.50","balance_used":100.0,"balance_remaining":0.0}}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+
+	api := newTestAPI(ts)
+	charge := &RecurringApplicationCharge{ID: 455696195, api: api}
+
+	if _, err := charge.CreateUsageCharge("Super Mega Plan 1000 emails", "4.51"); err != ErrUsageChargeExceedsBalance {
+		t.Errorf("expected ErrUsageChargeExceedsBalance, got %v", err)
+	}
+	if created != 0 {
+		t.Errorf("usage charge was created despite exceeding the cap")
+	}
+
+	usage, err := charge.CreateUsageCharge("Super Mega Plan 1000 emails", "4.50")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if usage.ID != 1034618207 || charge.BalanceRemaining != 0 || charge.BalanceUsed != 100 {
+		t.Errorf("unexpected result: %#v, charge %#v", usage, charge)
+	}
+}
+
+func TestCreateUsageChargeReloadsBalance(t *testing.T) {
+	balanceUsed := "50.0"
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(`{"recurring_application_charge":{"id":455696195,"status":"active","capped_amount":"100.00","balance_used":` + balanceUsed + `}}`))
+			return
+		}
+		w.WriteHeader(201)
+		w.Write([]byte(`{"usage_charge":{"id":1034618207,"price":"10.00"}}`))
+	}))
+	defer ts.Close()
+
+	api := newTestAPI(ts)
+	api.RequestCache = memoryRequestCache{}
+	charge := &RecurringApplicationCharge{ID: 455696195, api: api}
+
+	if _, err := charge.CreateUsageCharge("Emails", "10.00"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the charge reaches its cap elsewhere; a cached reload would miss it
+	balanceUsed = "100.0"
+	if _, err := charge.CreateUsageCharge("Emails", "10.00"); err != ErrUsageChargeExceedsBalance {
+		t.Errorf("expected ErrUsageChargeExceedsBalance, got %v", err)
+	}
+}
+
+func TestCreateUsageChargeRequiresActiveCharge(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("unexpected %s to %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"recurring_application_charge":{"id":455696195,"status":"pending","capped_amount":"100.00"}}`))
+	}))
+	defer ts.Close()
+
+	charge := &RecurringApplicationCharge{ID: 455696195, api: newTestAPI(ts)}
+	if _, err := charge.CreateUsageCharge("Super Mega Plan 1000 emails", "1.00"); err == nil {
+		t.Errorf("expected an error for a pending charge")
+	}
+}
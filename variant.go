@@ -1,9 +1,11 @@
 package shopify
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 )
 
 type Variant struct {
@@ -15,6 +17,7 @@ type Variant struct {
 	Weight               float64     `json:"weight,omitempty"`
 	WeightUnit           string      `json:"weight_unit,omitempty"`
 	ID                   int64       `json:"id,omitempty"`
+	InventoryItemID      int64       `json:"inventory_item_id,omitempty"`
 	InventoryManagement  string      `json:"inventory_management,omitempty"`
 	InventoryPolicy      string      `json:"inventory_policy,omitempty"`
 	InventoryQuantity    int64       `json:"inventory_quantity,omitempty"`
@@ -33,7 +36,283 @@ type Variant struct {
 	UpdatedAt            string      `json:"updated_at,omitempty"`
 	ImageID              int64       `json:"image_id,omitempty"`
 
-	api *API
+	api     *API
+	product *Product
+}
+
+type VariantOptions struct {
+	Limit   int    `url:"limit,omitempty"`
+	SinceID int64  `url:"since_id,omitempty"`
+	Fields  string `url:"fields,omitempty"`
+}
+
+// VariantPriceUpdate is one change for UpdateVariantPrices. An empty
+// CompareAtPrice is left unchanged.
+type VariantPriceUpdate struct {
+	VariantID      int64
+	Price          string
+	CompareAtPrice string
+}
+
+func (api *API) ProductVariants(productID int64, options *VariantOptions) ([]*Variant, *Pages, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("BASE_PATH/products/%d/variants.json?%v", productID, qs)
+	return api.processVariantsResponse(api.requestWithPagination(endpoint, "GET", nil, nil))
+}
+
+func (api *API) ProductVariantsFromPages(pages *Pages) ([]*Variant, *Pages, error) {
+	if pages.HasNextPage() {
+		return api.processVariantsResponse(api.getNextPage(pages))
+	}
+	return nil, &Pages{}, fmt.Errorf("No next page")
+}
+
+func (api *API) processVariantsResponse(res *bytes.Buffer, status int, pages *Pages, err error) ([]*Variant, *Pages, error) {
+	if err != nil {
+		return nil, pages, err
+	}
+
+	if status != 200 {
+		return nil, pages, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := &map[string][]*Variant{}
+	err = json.NewDecoder(res).Decode(r)
+	if err != nil {
+		return nil, pages, err
+	}
+
+	result := (*r)["variants"]
+	for _, v := range result {
+		v.api = api
+	}
+
+	return result, pages, nil
+}
+
+func (api *API) ProductVariantsCount(productID int64) (int, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/products/%d/variants/count.json", productID)
+
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if status != 200 {
+		return 0, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := map[string]interface{}{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	result, _ := strconv.Atoi(fmt.Sprintf("%v", r["count"]))
+	if err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+func (api *API) Variant(id int64) (*Variant, error) {
+	endpoint := fmt.Sprintf("BASE_PATH/variants/%d.json", id)
+
+	res, status, err := api.request(endpoint, "GET", nil, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := map[string]Variant{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	result := r["variant"]
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.api = api
+
+	return &result, nil
+}
+
+// NewVariant starts a variant for the product with the given id. Save loads
+// the product to check the variant's options against it.
+func (api *API) NewVariant(productID int64) *Variant {
+	return &Variant{ProductID: productID, api: api}
+}
+
+// NewVariant starts a variant whose options are checked against obj when
+// saved.
+func (obj *Product) NewVariant() *Variant {
+	return &Variant{ProductID: obj.ID, api: obj.api, product: obj}
+}
+
+// Save creates or updates just this variant, without sending the rest of
+// the product.
+func (obj *Variant) Save() error {
+	endpoint := fmt.Sprintf("BASE_PATH/variants/%d.json", obj.ID)
+	method := "PUT"
+	expectedStatus := 200
+
+	if obj.ID == 0 {
+		product := obj.product
+		if product == nil {
+			var err error
+			product, err = obj.api.Product(obj.ProductID)
+			if err != nil {
+				return err
+			}
+		}
+		if err := CheckVariantOptions(product, obj); err != nil {
+			return err
+		}
+
+		endpoint = fmt.Sprintf("BASE_PATH/products/%d/variants.json", obj.ProductID)
+		method = "POST"
+		expectedStatus = 201
+	}
+
+	body := map[string]*Variant{}
+	body["variant"] = obj
+
+	return obj.send(endpoint, method, expectedStatus, body)
+}
+
+func (obj *Variant) send(endpoint string, method string, expectedStatus int, body interface{}) error {
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(body)
+
+	if err != nil {
+		return err
+	}
+
+	res, status, err := obj.api.request(endpoint, method, nil, buf)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return fmt.Errorf("Status %d: %v", status, r.Errors)
+		}
+
+		return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+	}
+
+	r := map[string]Variant{}
+	err = json.NewDecoder(res).Decode(&r)
+
+	if err != nil {
+		return err
+	}
+
+	api := obj.api
+	product := obj.product
+	*obj = r["variant"]
+	obj.api = api
+	obj.product = product
+
+	return nil
+}
+
+func (obj *Variant) Delete() error {
+	endpoint := fmt.Sprintf("BASE_PATH/products/%d/variants/%d.json", obj.ProductID, obj.ID)
+	method := "DELETE"
+	expectedStatus := 200
+
+	res, status, err := obj.api.request(endpoint, method, nil, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if status != expectedStatus {
+		r := errorResponse{}
+		err = json.NewDecoder(res).Decode(&r)
+		if err == nil {
+			return fmt.Errorf("Status %d: %v", status, r.Errors)
+		}
+
+		return fmt.Errorf("Status %d, and error parsing body: %s", status, err)
+	}
+
+	return nil
+}
+
+// CheckVariantOptions makes sure variant has a value for each of the
+// product's options and no others, that each value is one of its option's
+// Values, and that it doesn't repeat the options of one of the product's
+// variants. Options loaded without their Values aren't checked against
+// them.
+func CheckVariantOptions(product *Product, variant *Variant) error {
+	if len(product.Options) > MAX_PRODUCT_OPTIONS {
+		return fmt.Errorf("Products can't have more than %d options", MAX_PRODUCT_OPTIONS)
+	}
+
+	values := []string{variant.Option1, variant.Option2, variant.Option3}
+	for i, value := range values {
+		switch {
+		case i < len(product.Options) && value == "":
+			return fmt.Errorf("Variant is missing a value for option %q", product.Options[i].Name)
+		case i >= len(product.Options) && value != "":
+			return fmt.Errorf("Product has no option %d for value %q", i+1, value)
+		case i < len(product.Options) && !optionHasValue(product.Options[i], value):
+			return fmt.Errorf("Option %q has no value %q", product.Options[i].Name, value)
+		}
+	}
+
+	for _, existing := range product.Variants {
+		if existing.ID != variant.ID &&
+			existing.Option1 == variant.Option1 &&
+			existing.Option2 == variant.Option2 &&
+			existing.Option3 == variant.Option3 {
+			return fmt.Errorf("Variant %d already has options %q", existing.ID, values[:len(product.Options)])
+		}
+	}
+
+	return nil
+}
+
+func optionHasValue(option Option, value string) bool {
+	if len(option.Values) == 0 {
+		return true
+	}
+	for _, v := range option.Values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateVariantPrices changes prices one variant at a time, so it works for
+// products with any number of variants and leaves their other fields alone.
+// It stops at the first failure, returning the variants updated so far.
+func (api *API) UpdateVariantPrices(updates []VariantPriceUpdate) ([]*Variant, error) {
+	result := make([]*Variant, 0, len(updates))
+	for _, update := range updates {
+		fields := map[string]interface{}{"id": update.VariantID, "price": update.Price}
+		if update.CompareAtPrice != "" {
+			fields["compare_at_price"] = update.CompareAtPrice
+		}
+
+		variant := &Variant{ID: update.VariantID, api: api}
+		endpoint := fmt.Sprintf("BASE_PATH/variants/%d.json", update.VariantID)
+		err := variant.send(endpoint, "PUT", 200, map[string]interface{}{"variant": fields})
+		if err != nil {
+			return result, fmt.Errorf("Updating variant %d: %s", update.VariantID, err)
+		}
+		result = append(result, variant)
+	}
+	return result, nil
 }
 
 func (obj *Variant) Metafields(options *MetafieldsOptions) ([]*Metafield, error) {
@@ -0,0 +1,229 @@
+package shopify
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const MAX_PRODUCT_OPTIONS = 3
+const MAX_PRODUCT_VARIANTS = 100
+
+// VariantRule overrides the matrix defaults for variants with an option
+// value, e.g. a higher price for size XL. Zero fields are left alone.
+type VariantRule struct {
+	Option string
+	Value  string
+	Price  string
+	Weight float64
+}
+
+// VariantMatrix builds a variant for every combination of its options'
+// values.
+type VariantMatrix struct {
+	Options []Option
+
+	// SKUTemplate derives each variant's SKU. {option1}, {option2} and
+	// {option3} are replaced with the variant's values, upper-cased and with
+	// anything but letters and digits turned into dashes; placeholders for
+	// options the matrix doesn't have are dropped along with the dashes
+	// around them. Leave empty for no SKUs.
+	SKUTemplate string
+
+	Price      string
+	Weight     float64
+	WeightUnit string
+
+	// Rules are applied in order, so later rules win.
+	Rules []VariantRule
+}
+
+var skuUnsafe = regexp.MustCompile(`[^A-Z0-9]+`)
+var skuUnusedOption = regexp.MustCompile(`-*\{option[1-3]\}`)
+
+// Variants returns the combinations, with the first option changing
+// slowest.
+func (m *VariantMatrix) Variants() ([]Variant, error) {
+	if err := m.check(); err != nil {
+		return nil, err
+	}
+
+	combinations := [][]string{{}}
+	for _, option := range m.Options {
+		next := make([][]string, 0, len(combinations)*len(option.Values))
+		for _, combination := range combinations {
+			for _, value := range option.Values {
+				c := append(append([]string{}, combination...), value)
+				next = append(next, c)
+			}
+		}
+		combinations = next
+	}
+
+	variants := make([]Variant, len(combinations))
+	for i, values := range combinations {
+		variants[i] = m.variant(values)
+		variants[i].Position = int64(i + 1)
+	}
+
+	return variants, nil
+}
+
+func (m *VariantMatrix) check() error {
+	if len(m.Options) == 0 {
+		return fmt.Errorf("Variant matrix needs at least one option")
+	}
+	if len(m.Options) > MAX_PRODUCT_OPTIONS {
+		return fmt.Errorf("Products can't have more than %d options", MAX_PRODUCT_OPTIONS)
+	}
+
+	count := 1
+	names := map[string]bool{}
+	for _, option := range m.Options {
+		if option.Name == "" || names[option.Name] {
+			return fmt.Errorf("Option names must be present and unique, got %q", option.Name)
+		}
+		names[option.Name] = true
+
+		if len(option.Values) == 0 {
+			return fmt.Errorf("Option %q has no values", option.Name)
+		}
+		values := map[string]bool{}
+		for _, value := range option.Values {
+			if value == "" || values[value] {
+				return fmt.Errorf("Option %q values must be present and unique, got %q", option.Name, value)
+			}
+			values[value] = true
+		}
+
+		count *= len(option.Values)
+	}
+
+	if count > MAX_PRODUCT_VARIANTS {
+		return fmt.Errorf("Options make %d variants, more than the limit of %d", count, MAX_PRODUCT_VARIANTS)
+	}
+	return nil
+}
+
+func (m *VariantMatrix) variant(values []string) Variant {
+	variant := Variant{
+		Price:      m.Price,
+		Weight:     m.Weight,
+		WeightUnit: m.WeightUnit,
+	}
+
+	options := []*string{&variant.Option1, &variant.Option2, &variant.Option3}
+	sku := m.SKUTemplate
+	for i, value := range values {
+		*options[i] = value
+		part := strings.Trim(skuUnsafe.ReplaceAllString(strings.ToUpper(value), "-"), "-")
+		sku = strings.Replace(sku, fmt.Sprintf("{option%d}", i+1), part, -1)
+	}
+	variant.Sku = strings.TrimLeft(skuUnusedOption.ReplaceAllString(sku, ""), "-")
+	variant.Title = strings.Join(values, " / ")
+
+	for _, rule := range m.Rules {
+		for i, option := range m.Options {
+			if option.Name != rule.Option || values[i] != rule.Value {
+				continue
+			}
+			if rule.Price != "" {
+				variant.Price = rule.Price
+			}
+			if rule.Weight != 0 {
+				variant.Weight = rule.Weight
+			}
+		}
+	}
+
+	return variant
+}
+
+// Apply sets the product's options and variants to the matrix. Variants
+// are matched to the product's existing ones by option name and value, so
+// adding, removing or reordering options keeps their IDs; matched variants
+// keep their other fields but take the matrix's options, position, SKU,
+// price and weight. New combinations are added and missing ones removed
+// when the product is saved.
+func (m *VariantMatrix) Apply(product *Product) error {
+	variants, err := m.Variants()
+	if err != nil {
+		return err
+	}
+
+	// match on the options both the product and the matrix have
+	shared := []string{}
+	for _, option := range m.Options {
+		for _, current := range product.Options {
+			if current.Name == option.Name {
+				shared = append(shared, option.Name)
+			}
+		}
+	}
+
+	existing := map[string][]Variant{}
+	for _, v := range product.Variants {
+		key := variantOptionKey(shared, product.Options, v)
+		existing[key] = append(existing[key], v)
+	}
+
+	for i, v := range variants {
+		key := variantOptionKey(shared, m.Options, v)
+		if matches := existing[key]; len(matches) > 0 {
+			variants[i] = m.update(matches[0], v)
+			existing[key] = matches[1:]
+		}
+		variants[i].api = product.api
+	}
+
+	options := make([]Option, len(m.Options))
+	for i, option := range m.Options {
+		options[i] = Option{Name: option.Name, Values: option.Values, Position: int64(i + 1)}
+		for _, current := range product.Options {
+			if current.Name == option.Name {
+				options[i].ID = current.ID
+				options[i].ProductID = current.ProductID
+			}
+		}
+	}
+
+	product.Options = options
+	product.Variants = variants
+	return nil
+}
+
+// update applies the matrix's variant for a combination to the existing
+// variant. Fields the matrix leaves empty keep their current value.
+func (m *VariantMatrix) update(current Variant, v Variant) Variant {
+	current.Option1, current.Option2, current.Option3 = v.Option1, v.Option2, v.Option3
+	current.Title = v.Title
+	current.Position = v.Position
+	if m.SKUTemplate != "" {
+		current.Sku = v.Sku
+	}
+	if v.Price != "" {
+		current.Price = v.Price
+	}
+	if v.Weight != 0 {
+		current.Weight = v.Weight
+	}
+	if v.WeightUnit != "" {
+		current.WeightUnit = v.WeightUnit
+	}
+	return current
+}
+
+// variantOptionKey joins the variant's values for the named options, where
+// options gives the order of the variant's Option1 to Option3.
+func variantOptionKey(names []string, options []Option, v Variant) string {
+	values := []string{v.Option1, v.Option2, v.Option3}
+	key := []string{}
+	for _, name := range names {
+		for i, option := range options {
+			if option.Name == name && i < len(values) {
+				key = append(key, values[i])
+			}
+		}
+	}
+	return strings.Join(key, "\x00")
+}
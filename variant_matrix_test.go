@@ -0,0 +1,150 @@
+package shopify
+
+import (
+	"testing"
+)
+
+func TestVariantMatrix(t *testing.T) {
+	m := &VariantMatrix{
+		Options: []Option{
+			{Name: "Size", Values: []string{"S", "XL"}},
+			{Name: "Color", Values: []string{"Navy Blue", "Red"}},
+		},
+		SKUTemplate: "TEE-{option1}-{option2}",
+		Price:       "20.00",
+		Weight:      0.2,
+		WeightUnit:  "kg",
+		Rules:       []VariantRule{{Option: "Size", Value: "XL", Price: "22.00", Weight: 0.3}},
+	}
+
+	variants, err := m.Variants()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(variants) != 4 {
+		t.Fatalf("expected 4 variants, got %d", len(variants))
+	}
+
+	first, last := variants[0], variants[3]
+	if first.Option1 != "S" || first.Option2 != "Navy Blue" || first.Sku != "TEE-S-NAVY-BLUE" || first.Price != "20.00" {
+		t.Errorf("unexpected first variant %+v", first)
+	}
+	if last.Option1 != "XL" || last.Option2 != "Red" || last.Price != "22.00" || last.Weight != 0.3 || last.Position != 4 {
+		t.Errorf("unexpected last variant %+v", last)
+	}
+}
+
+func TestVariantMatrixLimits(t *testing.T) {
+	values := func(n int) []string {
+		v := make([]string, n)
+		for i := range v {
+			v[i] = string(rune('A' + i))
+		}
+		return v
+	}
+
+	tooMany := &VariantMatrix{Options: []Option{
+		{Name: "A", Values: values(1)}, {Name: "B", Values: values(1)},
+		{Name: "C", Values: values(1)}, {Name: "D", Values: values(1)},
+	}}
+	if _, err := tooMany.Variants(); err == nil {
+		t.Errorf("expected an error for four options")
+	}
+
+	tooBig := &VariantMatrix{Options: []Option{{Name: "A", Values: values(11)}, {Name: "B", Values: values(10)}}}
+	if _, err := tooBig.Variants(); err == nil {
+		t.Errorf("expected an error for 110 variants")
+	}
+}
+
+func TestVariantMatrixApplyKeepsIDs(t *testing.T) {
+	product := &Product{
+		Options: []Option{{ID: 7, Name: "Size", Values: []string{"S", "M"}}},
+		Variants: []Variant{
+			{ID: 1, Option1: "S", Price: "18.00"},
+			{ID: 2, Option1: "M", Price: "18.00"},
+		},
+	}
+
+	m := &VariantMatrix{Options: []Option{{Name: "Size", Values: []string{"M", "L"}}}, Price: "20.00"}
+	if err := m.Apply(product); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(product.Variants) != 2 {
+		t.Fatalf("unexpected variants %+v", product.Variants)
+	}
+	if v := product.Variants[0]; v.ID != 2 || v.Price != "20.00" || v.Position != 1 {
+		t.Errorf("existing variant not updated: %+v", v)
+	}
+	if v := product.Variants[1]; v.ID != 0 || v.Option1 != "L" || v.Price != "20.00" {
+		t.Errorf("unexpected new variant %+v", v)
+	}
+	if product.Options[0].ID != 7 {
+		t.Errorf("option id lost: %+v", product.Options[0])
+	}
+}
+
+func TestVariantMatrixApplyMatchesByOptionName(t *testing.T) {
+	product := &Product{
+		Options: []Option{
+			{ID: 7, Name: "Size", Values: []string{"S", "M"}},
+			{ID: 8, Name: "Color", Values: []string{"Red"}},
+		},
+		Variants: []Variant{
+			{ID: 1, Option1: "S", Option2: "Red", Price: "18.00", Sku: "OLD-1", InventoryItemID: 11},
+			{ID: 2, Option1: "M", Option2: "Red", Price: "18.00", Sku: "OLD-2", InventoryItemID: 12},
+		},
+	}
+
+	// Color moves first and Material is added
+	m := &VariantMatrix{
+		Options: []Option{
+			{Name: "Color", Values: []string{"Red"}},
+			{Name: "Size", Values: []string{"S", "M"}},
+			{Name: "Material", Values: []string{"Cotton", "Linen"}},
+		},
+		SKUTemplate: "TEE-{option2}-{option1}",
+		Price:       "20.00",
+		Rules:       []VariantRule{{Option: "Size", Value: "M", Price: "21.00"}},
+	}
+	if err := m.Apply(product); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(product.Variants) != 4 {
+		t.Fatalf("unexpected variants %+v", product.Variants)
+	}
+	kept := map[int64]Variant{}
+	for _, v := range product.Variants {
+		if v.ID != 0 {
+			kept[v.ID] = v
+		}
+	}
+	if v := kept[1]; v.Option1 != "Red" || v.Option2 != "S" || v.Option3 != "Cotton" || v.Price != "20.00" || v.Sku != "TEE-S-RED" || v.InventoryItemID != 11 {
+		t.Errorf("variant 1 not updated: %+v", v)
+	}
+	if v := kept[2]; v.Option2 != "M" || v.Price != "21.00" || v.InventoryItemID != 12 {
+		t.Errorf("variant 2 not updated: %+v", v)
+	}
+	if len(kept) != 2 {
+		t.Errorf("expected both IDs kept, got %v", kept)
+	}
+	if product.Options[0].ID != 8 || product.Options[1].ID != 7 || product.Options[2].ID != 0 {
+		t.Errorf("option ids not kept: %+v", product.Options)
+	}
+}
+
+func TestVariantMatrixDropsUnusedSKUPlaceholders(t *testing.T) {
+	m := &VariantMatrix{
+		Options:     []Option{{Name: "Size", Values: []string{"S"}}},
+		SKUTemplate: "{option3}-TEE-{option1}-{option2}",
+	}
+	variants, err := m.Variants()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if variants[0].Sku != "TEE-S" {
+		t.Errorf("unexpected SKU %q", variants[0].Sku)
+	}
+}
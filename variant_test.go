@@ -0,0 +1,76 @@
+package shopify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckVariantOptions(t *testing.T) {
+	product := &Product{
+		Options: []Option{
+			{Name: "Size", Values: []string{"S", "M"}},
+			{Name: "Color", Values: []string{"Red"}},
+		},
+		Variants: []Variant{{ID: 1, Option1: "S", Option2: "Red"}},
+	}
+
+	cases := []struct {
+		variant Variant
+		valid   bool
+	}{
+		{Variant{Option1: "M", Option2: "Red"}, true},
+		{Variant{Option1: "M"}, false},
+		{Variant{Option1: "M", Option2: "Red", Option3: "Cotton"}, false},
+		{Variant{Option1: "S", Option2: "Red"}, false},
+		{Variant{ID: 1, Option1: "S", Option2: "Red"}, true},
+		{Variant{Option1: "XL", Option2: "Red"}, false},
+		{Variant{Option1: "M", Option2: "Blue"}, false},
+	}
+
+	for _, c := range cases {
+		err := CheckVariantOptions(product, &c.variant)
+		if (err == nil) != c.valid {
+			t.Errorf("%+v: expected valid=%v, got %v", c.variant, c.valid, err)
+		}
+	}
+
+	product.Options = append(product.Options, Option{Name: "Material"}, Option{Name: "Fit"})
+	if err := CheckVariantOptions(product, &Variant{Option1: "S", Option2: "Red", Option3: "Cotton"}); err == nil {
+		t.Errorf("expected an error for more than %d options", MAX_PRODUCT_OPTIONS)
+	}
+}
+
+func TestUpdateVariantPrices(t *testing.T) {
+	sent := map[string]map[string]interface{}{}
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		sent[r.URL.Path] = body["variant"]
+
+		if r.URL.Path == "/admin/api/2021-07/variants/3.json" {
+			w.WriteHeader(404)
+			w.Write([]byte(`{"errors":{"base":["Not Found"]}}`))
+			return
+		}
+		out, _ := json.Marshal(map[string]interface{}{"variant": body["variant"]})
+		w.Write(out)
+	}))
+	defer ts.Close()
+
+	updated, err := newTestAPI(ts).UpdateVariantPrices([]VariantPriceUpdate{
+		{VariantID: 1, Price: "10.00"},
+		{VariantID: 2, Price: "12.00", CompareAtPrice: "15.00"},
+		{VariantID: 3, Price: "9.00"},
+	})
+	if err == nil {
+		t.Errorf("expected an error for the missing variant")
+	}
+	if len(updated) != 2 || updated[1].Price != "12.00" || updated[1].CompareAtPrice != "15.00" {
+		t.Errorf("unexpected updates %+v", updated)
+	}
+	if len(sent["/admin/api/2021-07/variants/1.json"]) != 2 {
+		t.Errorf("expected only id and price, got %v", sent["/admin/api/2021-07/variants/1.json"])
+	}
+}
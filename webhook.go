@@ -20,7 +20,7 @@ type Webhook struct {
 }
 
 func (api *API) Webhooks() ([]*Webhook, error) {
-	res, status, err := api.request("/admin/webhooks.json", "GET", nil, nil)
+	res, status, err := api.request("BASE_PATH/webhooks.json", "GET", nil, nil)
 
 	if err != nil {
 		return nil, err
@@ -46,8 +46,54 @@ func (api *API) Webhooks() ([]*Webhook, error) {
 	return result, nil
 }
 
+type WebhookOptions struct {
+	Address string `url:"address,omitempty"`
+	Topic   string `url:"topic,omitempty"`
+	Limit   int    `url:"limit,omitempty"`
+	SinceID int64  `url:"since_id,omitempty"`
+	Fields  string `url:"fields,omitempty"`
+}
+
+// WebhooksWithOptions lists webhooks a page at a time; use
+// WebhooksFromPages for the rest.
+func (api *API) WebhooksWithOptions(options *WebhookOptions) ([]*Webhook, *Pages, error) {
+	qs := encodeOptions(options)
+	endpoint := fmt.Sprintf("BASE_PATH/webhooks.json?%v", qs)
+	return api.processWebhooksResponse(api.requestWithPagination(endpoint, "GET", nil, nil))
+}
+
+func (api *API) WebhooksFromPages(pages *Pages) ([]*Webhook, *Pages, error) {
+	if pages.HasNextPage() {
+		return api.processWebhooksResponse(api.getNextPage(pages))
+	}
+	return nil, &Pages{}, fmt.Errorf("No next page")
+}
+
+func (api *API) processWebhooksResponse(res *bytes.Buffer, status int, pages *Pages, err error) ([]*Webhook, *Pages, error) {
+	if err != nil {
+		return nil, pages, err
+	}
+
+	if status != 200 {
+		return nil, pages, fmt.Errorf("Status returned: %d", status)
+	}
+
+	r := &map[string][]*Webhook{}
+	err = json.NewDecoder(res).Decode(r)
+	if err != nil {
+		return nil, pages, err
+	}
+
+	result := (*r)["webhooks"]
+	for _, v := range result {
+		v.api = api
+	}
+
+	return result, pages, nil
+}
+
 func (api *API) Webhook(id int64) (*Webhook, error) {
-	endpoint := fmt.Sprintf("/admin/webhooks/%d.json", id)
+	endpoint := fmt.Sprintf("BASE_PATH/webhooks/%d.json", id)
 
 	res, status, err := api.request(endpoint, "GET", nil, nil)
 
@@ -78,12 +124,12 @@ func (api *API) NewWebhook() *Webhook {
 }
 
 func (obj *Webhook) Save(partial *Webhook) error {
-	endpoint := fmt.Sprintf("/admin/webhooks/%d.json", obj.Id)
+	endpoint := fmt.Sprintf("BASE_PATH/webhooks/%d.json", obj.Id)
 	method := "PUT"
 	expectedStatus := 200
 
 	if obj.Id == 0 {
-		endpoint = fmt.Sprintf("/admin/webhooks.json")
+		endpoint = fmt.Sprintf("BASE_PATH/webhooks.json")
 		method = "POST"
 		expectedStatus = 201
 	}
@@ -133,7 +179,7 @@ func (obj *Webhook) Save(partial *Webhook) error {
 }
 
 func (obj *Webhook) Delete() error {
-	endpoint := fmt.Sprintf("/admin/webhooks/%d.json", obj.Id)
+	endpoint := fmt.Sprintf("BASE_PATH/webhooks/%d.json", obj.Id)
 	method := "DELETE"
 	expectedStatus := 200
 
@@ -0,0 +1,198 @@
+package shopify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// WebhookEvent is a verified webhook delivery with its payload decoded into
+// the model registered for its topic.
+type WebhookEvent struct {
+	Topic      string `json:"topic"`
+	ShopDomain string `json:"shop_domain"`
+	WebhookID  string `json:"webhook_id"`
+	APIVersion string `json:"api_version"`
+	Body       []byte `json:"body"`
+
+	// Payload is a pointer to the registered model for Topic (e.g. *Order),
+	// or a json.RawMessage when the topic is not registered.
+	Payload interface{} `json:"-"`
+}
+
+// WebhookPayloadFactory returns an empty model for a topic, bound to api.
+type WebhookPayloadFactory func(api *API) interface{}
+
+// WebhookRegistry maps webhook topics to the REST models their payloads
+// decode into.
+type WebhookRegistry struct {
+	mu        sync.RWMutex
+	factories map[string]WebhookPayloadFactory
+}
+
+func newOrderPayload(api *API) interface{}      { return api.NewOrder() }
+func newProductPayload(api *API) interface{}    { return api.NewProduct() }
+func newCustomerPayload(api *API) interface{}   { return api.NewCustomer() }
+func newShopPayload(api *API) interface{}       { return &Shop{api: api} }
+func newThemePayload(api *API) interface{}      { return api.NewTheme() }
+func newCollectionPayload(api *API) interface{} { return api.NewCollection() }
+func newCheckoutPayload(api *API) interface{}   { return &Checkout{api: api} }
+
+var defaultWebhookPayloads = map[string]WebhookPayloadFactory{
+	"orders/create":              newOrderPayload,
+	"orders/updated":             newOrderPayload,
+	"orders/paid":                newOrderPayload,
+	"orders/cancelled":           newOrderPayload,
+	"orders/fulfilled":           newOrderPayload,
+	"orders/partially_fulfilled": newOrderPayload,
+	"orders/delete":              newOrderPayload,
+	"products/create":            newProductPayload,
+	"products/update":            newProductPayload,
+	"products/delete":            newProductPayload,
+	"customers/create":           newCustomerPayload,
+	"customers/update":           newCustomerPayload,
+	"customers/delete":           newCustomerPayload,
+	"customers/enable":           newCustomerPayload,
+	"customers/disable":          newCustomerPayload,
+	"app/uninstalled":            newShopPayload,
+	"shop/update":                newShopPayload,
+	"themes/create":              newThemePayload,
+	"themes/publish":             newThemePayload,
+	"themes/update":              newThemePayload,
+	"themes/delete":              newThemePayload,
+	"collections/create":         newCollectionPayload,
+	"collections/update":         newCollectionPayload,
+	"collections/delete":         newCollectionPayload,
+	"checkouts/create":           newCheckoutPayload,
+	"checkouts/update":           newCheckoutPayload,
+	"checkouts/delete":           newCheckoutPayload,
+	TopicCustomersDataRequest:    func(*API) interface{} { return &CustomersDataRequest{} },
+	TopicCustomersRedact:         func(*API) interface{} { return &CustomersRedact{} },
+	TopicShopRedact:              func(*API) interface{} { return &ShopRedact{} },
+	TopicAppSubscriptionsUpdate:  func(*API) interface{} { return &AppSubscriptionsUpdate{} },
+}
+
+// NewWebhookRegistry returns a registry preloaded with the topics this
+// package has models for.
+func NewWebhookRegistry() *WebhookRegistry {
+	r := &WebhookRegistry{factories: map[string]WebhookPayloadFactory{}}
+	for topic, factory := range defaultWebhookPayloads {
+		r.factories[topic] = factory
+	}
+	return r
+}
+
+// Register adds or replaces the model used for topic.
+func (r *WebhookRegistry) Register(topic string, factory WebhookPayloadFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[topic] = factory
+}
+
+// Decode unmarshals body into the model registered for topic and binds it to
+// api. Unknown topics are returned as json.RawMessage.
+func (r *WebhookRegistry) Decode(api *API, topic string, body []byte) (interface{}, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[topic]
+	r.mu.RUnlock()
+
+	if !ok {
+		raw := make(json.RawMessage, len(body))
+		copy(raw, body)
+		return raw, nil
+	}
+
+	result := factory(api)
+	if err := json.Unmarshal(body, result); err != nil {
+		return nil, fmt.Errorf("decoding %s payload: %s", topic, err)
+	}
+
+	switch v := result.(type) {
+	case *Product:
+		v.setAPI(api)
+	case *Order:
+		v.setAPI(api)
+	}
+
+	return result, nil
+}
+
+// WebhookHandler verifies incoming webhook requests and hands the typed
+// event to Handle.
+type WebhookHandler struct {
+	App      *App
+	Registry *WebhookRegistry
+
+	// APIForShop returns the client for the shop that sent the webhook, so
+	// decoded payloads can be saved straight back. If nil, payloads are bound
+	// to an API with only Shop set.
+	APIForShop func(shop string) (*API, error)
+
+	Handle func(event *WebhookEvent) error
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	event, status, err := readWebhookEvent(h.App, r)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	if err := h.decodeEvent(event); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Handle(event); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// readWebhookEvent verifies r and builds its event, returning the HTTP
+// status to respond with on failure. The payload is left undecoded.
+func readWebhookEvent(app *App, r *http.Request) (*WebhookEvent, int, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	if !app.VerifyHookRequest(r, body) {
+		return nil, http.StatusUnauthorized, fmt.Errorf("Invalid webhook signature")
+	}
+
+	event := &WebhookEvent{
+		Topic:      r.Header.Get("X-Shopify-Topic"),
+		ShopDomain: r.Header.Get("X-Shopify-Shop-Domain"),
+		WebhookID:  r.Header.Get("X-Shopify-Webhook-Id"),
+		APIVersion: r.Header.Get("X-Shopify-API-Version"),
+		Body:       body,
+	}
+
+	return event, 0, nil
+}
+
+// decodeEvent fills in event.Payload, bound to the source shop's API.
+func (h *WebhookHandler) decodeEvent(event *WebhookEvent) error {
+	var err error
+
+	api := &API{Shop: event.ShopDomain}
+	if h.APIForShop != nil {
+		api, err = h.APIForShop(event.ShopDomain)
+		if err != nil {
+			return err
+		}
+	}
+
+	registry := h.Registry
+	if registry == nil {
+		registry = NewWebhookRegistry()
+	}
+
+	event.Payload, err = registry.Decode(api, event.Topic, event.Body)
+	return err
+}
@@ -0,0 +1,107 @@
+package shopify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func signHook(secret string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(body)
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+func TestWebhookRegistryDecode(t *testing.T) {
+	registry := NewWebhookRegistry()
+	shopAPI := &API{Shop: "burnsmod.myshopify.com"}
+
+	payload, err := registry.Decode(shopAPI, "orders/create", []byte(`{"id":450789469,"email":"bob@example.com"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	order, ok := payload.(*Order)
+	if !ok {
+		t.Fatalf("expected *Order, got %T", payload)
+	}
+	if order.Id != 450789469 || order.Email != "bob@example.com" {
+		t.Errorf("order not decoded: %#v", order)
+	}
+	if order.api != shopAPI {
+		t.Errorf("order not bound to the shop API")
+	}
+
+	payload, err = registry.Decode(shopAPI, "products/update", []byte(`{"id":1,"variants":[{"id":2}]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	product := payload.(*Product)
+	if product.Variants[0].api != shopAPI {
+		t.Errorf("variants not bound to the shop API")
+	}
+
+	payload, err = registry.Decode(shopAPI, "app/uninstalled", []byte(`{"id":3,"myshopify_domain":"burnsmod.myshopify.com"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if shop, ok := payload.(*Shop); !ok || shop.MyshopifyDomain != "burnsmod.myshopify.com" {
+		t.Errorf("expected decoded *Shop, got %#v", payload)
+	}
+}
+
+func TestWebhookRegistryUnknownTopic(t *testing.T) {
+	payload, err := NewWebhookRegistry().Decode(&API{}, "bulk_operations/finish", []byte(`{"id":1}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if raw, ok := payload.(json.RawMessage); !ok || string(raw) != `{"id":1}` {
+		t.Errorf("expected raw JSON, got %#v", payload)
+	}
+}
+
+func TestWebhookHandler(t *testing.T) {
+	var received *WebhookEvent
+	handler := &WebhookHandler{
+		App: &app,
+		Handle: func(event *WebhookEvent) error {
+			received = event
+			return nil
+		},
+	}
+
+	body := []byte(`{"id":7,"name":"Debut"}`)
+
+	req := httptest.NewRequest("POST", "/hooks", bytes.NewReader(body))
+	req.Header.Set("X-Shopify-Topic", "themes/publish")
+	req.Header.Set("X-Shopify-Shop-Domain", "burnsmod.myshopify.com")
+	req.Header.Set("X-Shopify-Webhook-Id", "abc")
+	req.Header.Set("X-Shopify-Hmac-SHA256", signHook(app.APISecret, body))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	theme, ok := received.Payload.(*Theme)
+	if !ok || theme.Name != "Debut" {
+		t.Errorf("expected decoded *Theme, got %#v", received.Payload)
+	}
+	if theme.api == nil || theme.api.Shop != "burnsmod.myshopify.com" {
+		t.Errorf("theme not bound to the source shop")
+	}
+
+	req = httptest.NewRequest("POST", "/hooks", bytes.NewReader(body))
+	req.Header.Set("X-Shopify-Hmac-SHA256", "bogus")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for a bad signature, got %d", w.Code)
+	}
+}
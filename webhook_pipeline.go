@@ -0,0 +1,497 @@
+package shopify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jpillora/backoff"
+)
+
+const DEFAULT_WEBHOOK_SEEN_TTL = 48 * time.Hour
+const DEFAULT_WEBHOOK_ATTEMPTS = 5
+
+// DEFAULT_WEBHOOK_VISIBILITY_TIMEOUT is how long a popped job is hidden from
+// other Pops before it is handed out again if it hasn't been acked.
+const DEFAULT_WEBHOOK_VISIBILITY_TIMEOUT = 5 * time.Minute
+
+// WebhookJob is a queued webhook event and its delivery history.
+type WebhookJob struct {
+	Event     *WebhookEvent `json:"event"`
+	Attempts  int           `json:"attempts"`
+	NotBefore time.Time     `json:"not_before"`
+	LastError string        `json:"last_error,omitempty"`
+
+	// ref identifies the job inside the queue that returned it from Pop.
+	ref string
+}
+
+// WebhookSeenStore remembers webhook IDs so redelivered events are dropped.
+type WebhookSeenStore interface {
+	// MarkSeen records id for ttl and reports whether it was already seen.
+	MarkSeen(id string, ttl time.Duration) (bool, error)
+	// Forget removes id so a later delivery is accepted again.
+	Forget(id string) error
+}
+
+// WebhookQueue holds accepted events until a worker processes them.
+type WebhookQueue interface {
+	Push(job *WebhookJob) error
+	// Pop leases the next job whose NotBefore has passed, or returns nil if
+	// there is none. The job stays in the queue until it is acked; if it
+	// isn't acked within the queue's visibility timeout, Pop hands it out
+	// again.
+	Pop() (*WebhookJob, error)
+	Ack(job *WebhookJob) error
+}
+
+// WebhookDeadLetterStore receives jobs that ran out of attempts.
+type WebhookDeadLetterStore interface {
+	Put(job *WebhookJob) error
+}
+
+// memorySeenSweepInterval is how often MemorySeenStore drops expired IDs.
+const memorySeenSweepInterval = time.Minute
+
+// MemorySeenStore is an in-process WebhookSeenStore. Expired IDs are ignored
+// on lookup and swept out at most once a minute.
+type MemorySeenStore struct {
+	mu        sync.Mutex
+	seen      map[string]time.Time
+	nextSweep time.Time
+}
+
+func NewMemorySeenStore() *MemorySeenStore {
+	return &MemorySeenStore{seen: map[string]time.Time{}}
+}
+
+func (s *MemorySeenStore) MarkSeen(id string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.After(s.nextSweep) {
+		for k, expires := range s.seen {
+			if now.After(expires) {
+				delete(s.seen, k)
+			}
+		}
+		s.nextSweep = now.Add(memorySeenSweepInterval)
+	}
+
+	if expires, ok := s.seen[id]; ok && !now.After(expires) {
+		return true, nil
+	}
+	s.seen[id] = now.Add(ttl)
+	return false, nil
+}
+
+func (s *MemorySeenStore) Forget(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.seen, id)
+	return nil
+}
+
+// MemoryWebhookQueue is an in-process WebhookQueue. Jobs are lost on restart.
+type MemoryWebhookQueue struct {
+	// VisibilityTimeout defaults to DEFAULT_WEBHOOK_VISIBILITY_TIMEOUT.
+	VisibilityTimeout time.Duration
+
+	mu   sync.Mutex
+	seq  int64
+	jobs []*memoryWebhookJob
+}
+
+type memoryWebhookJob struct {
+	job         *WebhookJob
+	ref         string
+	leasedUntil time.Time
+}
+
+func NewMemoryWebhookQueue() *MemoryWebhookQueue {
+	return &MemoryWebhookQueue{}
+}
+
+func (q *MemoryWebhookQueue) Push(job *WebhookJob) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.seq++
+	q.jobs = append(q.jobs, &memoryWebhookJob{job: job, ref: fmt.Sprint(q.seq)})
+	return nil
+}
+
+func (q *MemoryWebhookQueue) Pop() (*WebhookJob, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	for _, entry := range q.jobs {
+		if entry.job.NotBefore.After(now) || entry.leasedUntil.After(now) {
+			continue
+		}
+		entry.leasedUntil = now.Add(visibilityTimeout(q.VisibilityTimeout))
+
+		job := *entry.job
+		job.ref = entry.ref
+		return &job, nil
+	}
+	return nil, nil
+}
+
+func (q *MemoryWebhookQueue) Ack(job *WebhookJob) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, entry := range q.jobs {
+		if entry.ref == job.ref {
+			q.jobs = append(q.jobs[:i], q.jobs[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// Len returns the number of queued jobs, including leased ones.
+func (q *MemoryWebhookQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.jobs)
+}
+
+func visibilityTimeout(timeout time.Duration) time.Duration {
+	if timeout == 0 {
+		return DEFAULT_WEBHOOK_VISIBILITY_TIMEOUT
+	}
+	return timeout
+}
+
+// FileWebhookQueue stores each job as a JSON file in Dir, named for when it
+// is due so Pop only reads jobs it can hand out. Jobs that were popped but
+// never acked are handed out again after the visibility timeout or a
+// restart. Files that can't be decoded are moved to Dir/corrupt.
+type FileWebhookQueue struct {
+	Dir string
+
+	// VisibilityTimeout defaults to DEFAULT_WEBHOOK_VISIBILITY_TIMEOUT.
+	VisibilityTimeout time.Duration
+
+	mu       sync.Mutex
+	seq      int64
+	inflight map[string]time.Time
+}
+
+func NewFileWebhookQueue(dir string) (*FileWebhookQueue, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileWebhookQueue{Dir: dir, inflight: map[string]time.Time{}}, nil
+}
+
+func (q *FileWebhookQueue) Push(job *WebhookJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	due := time.Now()
+	if job.NotBefore.After(due) {
+		due = job.NotBefore
+	}
+
+	q.mu.Lock()
+	q.seq++
+	name := fmt.Sprintf("%020d-%06d.json", due.UnixNano(), q.seq%1000000)
+	q.mu.Unlock()
+
+	tmp := filepath.Join(q.Dir, name+".tmp")
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(q.Dir, name))
+}
+
+func (q *FileWebhookQueue) Pop() (*WebhookJob, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	files, err := ioutil.ReadDir(q.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	names := []string{}
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		if q.inflight[f.Name()].After(now) {
+			continue
+		}
+		names = append(names, f.Name())
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		// names sort by due time, so the rest aren't due either
+		if due, ok := fileWebhookJobDue(name); ok && due.After(now) {
+			break
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(q.Dir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		job := &WebhookJob{}
+		if err := json.Unmarshal(data, job); err != nil {
+			if err := q.quarantine(name); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		job.ref = name
+		q.inflight[name] = now.Add(visibilityTimeout(q.VisibilityTimeout))
+		return job, nil
+	}
+	return nil, nil
+}
+
+// fileWebhookJobDue reads the due time from a job's file name.
+func fileWebhookJobDue(name string) (time.Time, bool) {
+	i := strings.Index(name, "-")
+	if i < 0 {
+		return time.Time{}, false
+	}
+	nanos, err := strconv.ParseInt(name[:i], 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, nanos), true
+}
+
+// quarantine moves an unreadable job out of the way so it doesn't block the
+// rest of the queue.
+func (q *FileWebhookQueue) quarantine(name string) error {
+	dir := filepath.Join(q.Dir, "corrupt")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	delete(q.inflight, name)
+	return os.Rename(filepath.Join(q.Dir, name), filepath.Join(dir, name))
+}
+
+func (q *FileWebhookQueue) Ack(job *WebhookJob) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	err := os.Remove(filepath.Join(q.Dir, job.ref))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	delete(q.inflight, job.ref)
+	return nil
+}
+
+// MemoryDeadLetterStore keeps failed jobs in memory for inspection.
+type MemoryDeadLetterStore struct {
+	mu   sync.Mutex
+	jobs []*WebhookJob
+}
+
+func (s *MemoryDeadLetterStore) Put(job *WebhookJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, job)
+	return nil
+}
+
+// Jobs returns the dead-lettered jobs in the order they failed.
+func (s *MemoryDeadLetterStore) Jobs() []*WebhookJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*WebhookJob{}, s.jobs...)
+}
+
+// WebhookPipeline acknowledges verified webhooks as soon as they are queued
+// and processes them with a pool of workers, so slow handlers don't cause
+// Shopify to drop the subscription.
+type WebhookPipeline struct {
+	// Handler verifies requests and decodes payloads; its Handle func is
+	// what the workers run.
+	Handler *WebhookHandler
+
+	Seen    WebhookSeenStore
+	SeenTTL time.Duration
+	Queue   WebhookQueue
+
+	// DeadLetter is required; it receives jobs that failed MaxAttempts
+	// times, which are otherwise lost.
+	DeadLetter WebhookDeadLetterStore
+
+	Workers      int
+	MaxAttempts  int
+	Backoff      *backoff.Backoff
+	PollInterval time.Duration
+
+	wake   chan struct{}
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func (p *WebhookPipeline) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	event, status, err := readWebhookEvent(p.Handler.App, r)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	if p.Seen != nil && event.WebhookID != "" {
+		ttl := p.SeenTTL
+		if ttl == 0 {
+			ttl = DEFAULT_WEBHOOK_SEEN_TTL
+		}
+		seen, err := p.Seen.MarkSeen(event.WebhookID, ttl)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if seen {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+	}
+
+	if err := p.Queue.Push(&WebhookJob{Event: event}); err != nil {
+		// let Shopify redeliver it
+		if p.Seen != nil && event.WebhookID != "" {
+			p.Seen.Forget(event.WebhookID)
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	p.notify()
+	w.WriteHeader(http.StatusOK)
+}
+
+// Start launches the worker pool. Call Stop to shut it down.
+func (p *WebhookPipeline) Start() error {
+	if p.Handler == nil || p.Handler.Handle == nil {
+		return errors.New("WebhookPipeline needs a Handler with a Handle func")
+	}
+	if p.Queue == nil || p.DeadLetter == nil {
+		return errors.New("WebhookPipeline needs a Queue and a DeadLetter store")
+	}
+	if p.Workers == 0 {
+		p.Workers = 1
+	}
+	if p.MaxAttempts == 0 {
+		p.MaxAttempts = DEFAULT_WEBHOOK_ATTEMPTS
+	}
+	if p.PollInterval == 0 {
+		p.PollInterval = time.Second
+	}
+	if p.Backoff == nil {
+		p.Backoff = &backoff.Backoff{
+			Min:    time.Second,
+			Max:    5 * time.Minute,
+			Jitter: true,
+		}
+	}
+	p.wake = make(chan struct{}, p.Workers)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	for i := 0; i < p.Workers; i++ {
+		p.wg.Add(1)
+		go p.work(ctx)
+	}
+	return nil
+}
+
+// Stop waits for running jobs to finish and stops the workers.
+func (p *WebhookPipeline) Stop() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.wg.Wait()
+}
+
+func (p *WebhookPipeline) notify() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (p *WebhookPipeline) work(ctx context.Context) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		job, err := p.Queue.Pop()
+		if err == nil && job != nil {
+			p.process(job)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *WebhookPipeline) process(job *WebhookJob) {
+	err := p.Handler.decodeEvent(job.Event)
+	if err == nil {
+		err = p.Handler.Handle(job.Event)
+	}
+	if err == nil {
+		p.Queue.Ack(job)
+		return
+	}
+
+	next := &WebhookJob{
+		Event:     job.Event,
+		Attempts:  job.Attempts + 1,
+		LastError: err.Error(),
+	}
+
+	// If storing the next step fails, the job is left unacked and the
+	// queue hands it out again once its lease expires.
+	if next.Attempts >= p.MaxAttempts {
+		if err := p.DeadLetter.Put(next); err != nil {
+			return
+		}
+		p.Queue.Ack(job)
+		return
+	}
+
+	next.NotBefore = time.Now().Add(p.Backoff.ForAttempt(float64(job.Attempts)))
+	if err := p.Queue.Push(next); err != nil {
+		return
+	}
+	p.Queue.Ack(job)
+}
@@ -0,0 +1,291 @@
+package shopify
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jpillora/backoff"
+)
+
+func postHook(p *WebhookPipeline, id string, body []byte) int {
+	req := httptest.NewRequest("POST", "/hooks", bytes.NewReader(body))
+	req.Header.Set("X-Shopify-Topic", "orders/create")
+	req.Header.Set("X-Shopify-Shop-Domain", "burnsmod.myshopify.com")
+	req.Header.Set("X-Shopify-Webhook-Id", id)
+	req.Header.Set("X-Shopify-Hmac-SHA256", signHook(app.APISecret, body))
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestWebhookPipelineDeduplicates(t *testing.T) {
+	queue := NewMemoryWebhookQueue()
+	p := &WebhookPipeline{
+		Handler: &WebhookHandler{App: &app},
+		Seen:    NewMemorySeenStore(),
+		Queue:   queue,
+	}
+
+	body := []byte(`{"id":1}`)
+	for i := 0; i < 3; i++ {
+		if code := postHook(p, "abc", body); code != 200 {
+			t.Fatalf("expected 200, got %d", code)
+		}
+	}
+	postHook(p, "def", body)
+
+	if queue.Len() != 2 {
+		t.Errorf("expected 2 queued jobs, got %d", queue.Len())
+	}
+}
+
+func TestMemorySeenStoreExpires(t *testing.T) {
+	s := NewMemorySeenStore()
+	if seen, _ := s.MarkSeen("abc", time.Hour); seen {
+		t.Errorf("expected a new id not to be seen")
+	}
+	if seen, _ := s.MarkSeen("abc", time.Hour); !seen {
+		t.Errorf("expected a repeated id to be seen")
+	}
+
+	// expired between sweeps
+	s.MarkSeen("def", time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if seen, _ := s.MarkSeen("def", time.Hour); seen {
+		t.Errorf("expected an expired id not to be seen")
+	}
+}
+
+func TestWebhookPipelineRetriesToDeadLetter(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	done := make(chan struct{})
+
+	deadLetter := &MemoryDeadLetterStore{}
+	p := &WebhookPipeline{
+		Handler: &WebhookHandler{
+			App: &app,
+			Handle: func(event *WebhookEvent) error {
+				mu.Lock()
+				defer mu.Unlock()
+				calls++
+				if calls == 3 {
+					close(done)
+				}
+				return errors.New("downstream unavailable")
+			},
+		},
+		Queue:        NewMemoryWebhookQueue(),
+		DeadLetter:   deadLetter,
+		MaxAttempts:  3,
+		PollInterval: time.Millisecond,
+		Backoff:      &backoff.Backoff{Min: time.Millisecond, Max: time.Millisecond},
+	}
+	p.Start()
+	postHook(p, "abc", []byte(`{"id":1}`))
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("handler was not retried")
+	}
+	p.Stop()
+
+	jobs := deadLetter.Jobs()
+	if len(jobs) != 1 {
+		t.Fatalf("expected 1 dead-lettered job, got %d", len(jobs))
+	}
+	if jobs[0].Attempts != 3 || jobs[0].LastError != "downstream unavailable" {
+		t.Errorf("unexpected dead letter: %#v", jobs[0])
+	}
+}
+
+func TestFileWebhookQueue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webhooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q, _ := NewFileWebhookQueue(dir)
+	q.Push(&WebhookJob{Event: &WebhookEvent{WebhookID: "first"}})
+	q.Push(&WebhookJob{Event: &WebhookEvent{WebhookID: "later"}, NotBefore: time.Now().Add(time.Hour)})
+
+	job, err := q.Pop()
+	if err != nil || job == nil || job.Event.WebhookID != "first" {
+		t.Fatalf("expected first job, got %#v (%v)", job, err)
+	}
+	if next, _ := q.Pop(); next != nil {
+		t.Errorf("expected no ready job, got %#v", next)
+	}
+
+	// a new queue over the same directory sees the unacked job again
+	restarted, _ := NewFileWebhookQueue(dir)
+	job, _ = restarted.Pop()
+	if job == nil || job.Event.WebhookID != "first" {
+		t.Fatalf("expected unacked job after restart, got %#v", job)
+	}
+	if err := restarted.Ack(job); err != nil {
+		t.Fatal(err)
+	}
+	if next, _ := restarted.Pop(); next != nil {
+		t.Errorf("expected acked job to be gone, got %#v", next)
+	}
+}
+
+func TestFileWebhookQueueSkipsJobsNotDue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webhooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q, _ := NewFileWebhookQueue(dir)
+	q.Push(&WebhookJob{Event: &WebhookEvent{WebhookID: "later"}, NotBefore: time.Now().Add(time.Hour)})
+
+	// the job isn't due, so Pop shouldn't read it and find it corrupt
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 1 {
+		t.Fatalf("expected one queued job, got %d files", len(files))
+	}
+	ioutil.WriteFile(filepath.Join(dir, files[0].Name()), []byte("{not json"), 0600)
+
+	if job, err := q.Pop(); err != nil || job != nil {
+		t.Errorf("expected no ready job, got %#v (%v)", job, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, files[0].Name())); err != nil {
+		t.Errorf("expected the job not to be read before it is due: %s", err)
+	}
+}
+
+type flakyWebhookQueue struct {
+	*MemoryWebhookQueue
+	failPush bool
+}
+
+func (q *flakyWebhookQueue) Push(job *WebhookJob) error {
+	if q.failPush {
+		return errors.New("queue unavailable")
+	}
+	return q.MemoryWebhookQueue.Push(job)
+}
+
+type flakyDeadLetterStore struct {
+	MemoryDeadLetterStore
+	fail bool
+}
+
+func (s *flakyDeadLetterStore) Put(job *WebhookJob) error {
+	if s.fail {
+		return errors.New("dead letter store unavailable")
+	}
+	return s.MemoryDeadLetterStore.Put(job)
+}
+
+func TestWebhookPipelineKeepsJobsWhenStoresFail(t *testing.T) {
+	queue := &flakyWebhookQueue{MemoryWebhookQueue: &MemoryWebhookQueue{VisibilityTimeout: 10 * time.Millisecond}}
+	deadLetter := &flakyDeadLetterStore{fail: true}
+	p := &WebhookPipeline{
+		Handler: &WebhookHandler{
+			App:    &app,
+			Handle: func(event *WebhookEvent) error { return errors.New("downstream unavailable") },
+		},
+		Queue:       queue,
+		DeadLetter:  deadLetter,
+		MaxAttempts: 2,
+		Backoff:     &backoff.Backoff{Min: time.Millisecond, Max: time.Millisecond},
+	}
+
+	event := &WebhookEvent{Topic: "orders/create", Body: []byte(`{"id":1}`)}
+	queue.Push(&WebhookJob{Event: event})
+	queue.failPush = true
+
+	// the retry can't be queued, so the original job must survive
+	job, _ := queue.Pop()
+	p.process(job)
+	if queue.Len() != 1 {
+		t.Fatalf("expected the job to stay queued, got %d jobs", queue.Len())
+	}
+	if leased, _ := queue.Pop(); leased != nil {
+		t.Fatalf("expected the job to be leased, got %#v", leased)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	job, _ = queue.Pop()
+	if job == nil || job.Attempts != 0 {
+		t.Fatalf("expected the unacked job back, got %#v", job)
+	}
+
+	// the last attempt can't be dead-lettered, so the job must survive
+	queue.failPush = false
+	job.Attempts = 1
+	p.process(job)
+	if queue.Len() != 1 || len(deadLetter.Jobs()) != 0 {
+		t.Fatalf("expected the job to stay queued, got %d jobs and %d dead letters", queue.Len(), len(deadLetter.Jobs()))
+	}
+
+	deadLetter.fail = false
+	time.Sleep(20 * time.Millisecond)
+	job, _ = queue.Pop()
+	job.Attempts = 1
+	p.process(job)
+	if queue.Len() != 0 || len(deadLetter.Jobs()) != 1 {
+		t.Errorf("expected the job to be dead-lettered, got %d jobs and %d dead letters", queue.Len(), len(deadLetter.Jobs()))
+	}
+}
+
+func TestFileWebhookQueueQuarantinesCorruptJobs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webhooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q, _ := NewFileWebhookQueue(dir)
+	ioutil.WriteFile(filepath.Join(dir, "00000000000000000000-000000.json"), []byte("{not json"), 0600)
+	q.Push(&WebhookJob{Event: &WebhookEvent{WebhookID: "good"}})
+
+	for i := 0; i < 2; i++ {
+		job, err := q.Pop()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 && (job == nil || job.Event.WebhookID != "good") {
+			t.Fatalf("expected the good job, got %#v", job)
+		}
+		if i == 1 && job != nil {
+			t.Fatalf("expected the good job to be leased, got %#v", job)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "corrupt", "00000000000000000000-000000.json")); err != nil {
+		t.Errorf("expected the corrupt job to be quarantined: %s", err)
+	}
+}
+
+func TestWebhookPipelineRequiresDeadLetter(t *testing.T) {
+	handle := func(event *WebhookEvent) error { return nil }
+	p := &WebhookPipeline{Handler: &WebhookHandler{App: &app, Handle: handle}, Queue: NewMemoryWebhookQueue()}
+	if err := p.Start(); err == nil {
+		t.Errorf("expected an error without a dead letter store")
+	}
+}
+
+func TestWebhookPipelineRequiresHandle(t *testing.T) {
+	p := &WebhookPipeline{Queue: NewMemoryWebhookQueue(), DeadLetter: &MemoryDeadLetterStore{}}
+	if err := p.Start(); err == nil {
+		t.Errorf("expected an error without a handler")
+	}
+
+	p.Handler = &WebhookHandler{App: &app}
+	if err := p.Start(); err == nil {
+		t.Errorf("expected an error without a Handle func")
+	}
+}
@@ -0,0 +1,227 @@
+package shopify
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+const (
+	WebhookCreate   = "create"
+	WebhookUpdate   = "update"
+	WebhookRecreate = "recreate"
+	WebhookDelete   = "delete"
+)
+
+// WebhookSubscription is a webhook the app wants to have registered.
+type WebhookSubscription struct {
+	Topic               string
+	Address             string
+	Format              string // defaults to "json"
+	Fields              []string
+	MetafieldNamespaces []string
+}
+
+// WebhookOperation is one change needed to reach the desired subscriptions.
+// Current is nil for creates; Desired is nil for deletes.
+type WebhookOperation struct {
+	Action  string
+	Desired *WebhookSubscription
+	Current *Webhook
+}
+
+func (op WebhookOperation) String() string {
+	switch op.Action {
+	case WebhookCreate:
+		return fmt.Sprintf("create %s -> %s", op.Desired.Topic, op.Desired.Address)
+	case WebhookDelete:
+		return fmt.Sprintf("delete %s -> %s (id %d)", op.Current.Topic, op.Current.Address, op.Current.Id)
+	}
+	return fmt.Sprintf("%s %s -> %s (id %d)", op.Action, op.Desired.Topic, op.Desired.Address, op.Current.Id)
+}
+
+// WebhookReconciler brings a shop's webhooks in line with Desired.
+// Subscriptions are matched on topic and address.
+type WebhookReconciler struct {
+	API     *API
+	Desired []WebhookSubscription
+
+	// DryRun prints the plan to Out (stdout by default) without applying it.
+	DryRun bool
+	Out    io.Writer
+}
+
+// Plan lists every current webhook and returns the operations needed.
+func (r *WebhookReconciler) Plan() ([]WebhookOperation, error) {
+	current := []*Webhook{}
+	hooks, pages, err := r.API.WebhooksWithOptions(&WebhookOptions{Limit: 250})
+	for {
+		if err != nil {
+			return nil, err
+		}
+		current = append(current, hooks...)
+		if !pages.HasNextPage() {
+			break
+		}
+		hooks, pages, err = r.API.WebhooksFromPages(pages)
+	}
+	return planWebhooks(r.Desired, current), nil
+}
+
+// Reconcile computes the plan and, unless DryRun is set, applies it. The
+// plan is returned either way.
+func (r *WebhookReconciler) Reconcile() ([]WebhookOperation, error) {
+	plan, err := r.Plan()
+	if err != nil {
+		return nil, err
+	}
+
+	if r.DryRun {
+		out := r.Out
+		if out == nil {
+			out = os.Stdout
+		}
+		for _, op := range plan {
+			fmt.Fprintln(out, op)
+		}
+		return plan, nil
+	}
+
+	for _, op := range plan {
+		if err := r.apply(op); err != nil {
+			return plan, fmt.Errorf("%s: %s", op, err)
+		}
+	}
+	return plan, nil
+}
+
+func (r *WebhookReconciler) apply(op WebhookOperation) error {
+	switch op.Action {
+	case WebhookCreate:
+		hook := r.API.NewWebhook()
+		op.Desired.copyTo(hook)
+		return hook.Save(nil)
+	case WebhookUpdate:
+		partial := &Webhook{Id: op.Current.Id}
+		op.Desired.copyTo(partial)
+		return op.Current.Save(partial)
+	case WebhookRecreate:
+		// Shopify allows one webhook per topic and address, so the old one
+		// has to go before its replacement can be created
+		if err := op.Current.Delete(); err != nil {
+			return err
+		}
+		hook := r.API.NewWebhook()
+		op.Desired.copyTo(hook)
+		return hook.Save(nil)
+	case WebhookDelete:
+		return op.Current.Delete()
+	}
+	return fmt.Errorf("unknown action %q", op.Action)
+}
+
+func (s *WebhookSubscription) format() string {
+	if s.Format == "" {
+		return "json"
+	}
+	return s.Format
+}
+
+func (s *WebhookSubscription) copyTo(hook *Webhook) {
+	hook.Topic = s.Topic
+	hook.Address = s.Address
+	hook.Format = s.format()
+	hook.Fields = toInterfaces(s.Fields)
+	hook.MetafieldNamespaces = toInterfaces(s.MetafieldNamespaces)
+}
+
+func planWebhooks(desired []WebhookSubscription, current []*Webhook) []WebhookOperation {
+	existing := map[string]*Webhook{}
+	plan := []WebhookOperation{}
+
+	for _, hook := range current {
+		key := hook.Topic + " " + hook.Address
+		if _, ok := existing[key]; ok {
+			plan = append(plan, WebhookOperation{Action: WebhookDelete, Current: hook})
+			continue
+		}
+		existing[key] = hook
+	}
+
+	wanted := map[string]bool{}
+	for i := range desired {
+		want := &desired[i]
+		key := want.Topic + " " + want.Address
+		if wanted[key] {
+			continue
+		}
+		wanted[key] = true
+
+		hook, ok := existing[key]
+		if !ok {
+			plan = append(plan, WebhookOperation{Action: WebhookCreate, Desired: want})
+			continue
+		}
+
+		fields := fromInterfaces(hook.Fields)
+		namespaces := fromInterfaces(hook.MetafieldNamespaces)
+		if hook.Format == want.format() &&
+			sameStringSet(fields, want.Fields) &&
+			sameStringSet(namespaces, want.MetafieldNamespaces) {
+			continue
+		}
+
+		// Fields and namespaces are omitted from the request when empty, so
+		// clearing them needs a fresh subscription.
+		action := WebhookUpdate
+		if (len(want.Fields) == 0 && len(fields) > 0) ||
+			(len(want.MetafieldNamespaces) == 0 && len(namespaces) > 0) {
+			action = WebhookRecreate
+		}
+		plan = append(plan, WebhookOperation{Action: action, Desired: want, Current: hook})
+	}
+
+	for _, hook := range current {
+		if !wanted[hook.Topic+" "+hook.Address] && existing[hook.Topic+" "+hook.Address] == hook {
+			plan = append(plan, WebhookOperation{Action: WebhookDelete, Current: hook})
+		}
+	}
+
+	return plan
+}
+
+func toInterfaces(values []string) []interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+	result := make([]interface{}, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+	return result
+}
+
+func fromInterfaces(values []interface{}) []string {
+	result := []string{}
+	for _, v := range values {
+		result = append(result, fmt.Sprintf("%v", v))
+	}
+	return result
+}
+
+func sameStringSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string{}, a...)
+	b = append([]string{}, b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+package shopify
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPlanWebhooks(t *testing.T) {
+	desired := []WebhookSubscription{
+		{Topic: "orders/create", Address: "https://app.com/hooks"},
+		{Topic: "products/update", Address: "https://app.com/hooks", Fields: []string{"id", "title"}},
+		{Topic: "app/uninstalled", Address: "https://app.com/hooks"},
+		{Topic: "themes/publish", Address: "https://app.com/hooks"},
+	}
+	current := []*Webhook{
+		{Id: 1, Topic: "orders/create", Address: "https://app.com/hooks", Format: "json"},
+		{Id: 2, Topic: "orders/create", Address: "https://app.com/hooks", Format: "json"},
+		{Id: 3, Topic: "products/update", Address: "https://app.com/hooks", Format: "json", Fields: []interface{}{"title", "id", "handle"}},
+		{Id: 4, Topic: "themes/publish", Address: "https://app.com/hooks", Format: "json", Fields: []interface{}{"id"}},
+		{Id: 5, Topic: "customers/create", Address: "https://old.app.com/hooks", Format: "json"},
+	}
+
+	plan := planWebhooks(desired, current)
+
+	expected := []struct {
+		action string
+		id     int64
+		topic  string
+	}{
+		{WebhookDelete, 2, "orders/create"},
+		{WebhookUpdate, 3, "products/update"},
+		{WebhookCreate, 0, "app/uninstalled"},
+		{WebhookRecreate, 4, "themes/publish"},
+		{WebhookDelete, 5, "customers/create"},
+	}
+
+	if len(plan) != len(expected) {
+		t.Fatalf("expected %d operations, got %d: %v", len(expected), len(plan), plan)
+	}
+
+	for i, e := range expected {
+		op := plan[i]
+		if op.Action != e.action {
+			t.Errorf("operation %d: expected %s, got %s", i, e.action, op)
+		}
+		if op.Current != nil && op.Current.Id != e.id {
+			t.Errorf("operation %d: expected webhook %d, got %s", i, e.id, op)
+		}
+		if op.Desired != nil && op.Desired.Topic != e.topic {
+			t.Errorf("operation %d: expected topic %s, got %s", i, e.topic, op)
+		}
+	}
+}
+
+func TestWebhookReconcilerPagesAndRecreates(t *testing.T) {
+	var ts *httptest.Server
+	requests := []string{}
+	deleted := false
+	ts = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Query().Get("page_info") == "":
+			w.Header().Set("Link", `<`+ts.URL+`/admin/api/2021-07/webhooks.json?page_info=abc>; rel="next"`)
+			w.Write([]byte(`{"webhooks":[{"id":1,"topic":"orders/create","address":"https://app.com/hooks","format":"json"}]}`))
+		case r.Method == "GET":
+			w.Write([]byte(`{"webhooks":[{"id":2,"topic":"themes/publish","address":"https://app.com/hooks","format":"json","fields":["id"]}]}`))
+		case r.Method == "POST" && !deleted:
+			// like Shopify, one webhook per topic and address
+			requests = append(requests, "create")
+			w.WriteHeader(422)
+			w.Write([]byte(`{"errors":{"address":["for this topic has already been taken"]}}`))
+		case r.Method == "POST":
+			requests = append(requests, "create")
+			w.WriteHeader(201)
+			w.Write([]byte(`{"webhook":{"id":3,"topic":"themes/publish","address":"https://app.com/hooks","format":"json"}}`))
+		case r.Method == "DELETE":
+			requests = append(requests, "delete "+r.URL.Path)
+			deleted = true
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer ts.Close()
+
+	r := &WebhookReconciler{
+		API: newTestAPI(ts),
+		Desired: []WebhookSubscription{
+			{Topic: "orders/create", Address: "https://app.com/hooks"},
+			{Topic: "themes/publish", Address: "https://app.com/hooks"},
+		},
+	}
+
+	plan, err := r.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) != 1 || plan[0].Action != WebhookRecreate {
+		t.Fatalf("expected the second page's webhook to be recreated, got %v", plan)
+	}
+	if len(requests) != 2 || requests[0] != "delete /admin/api/2021-07/webhooks/2.json" || requests[1] != "create" {
+		t.Errorf("expected delete then create, got %v", requests)
+	}
+}